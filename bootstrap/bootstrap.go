@@ -0,0 +1,260 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/migrate"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/isolation"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// tables are listed in foreign key order, so they can be copied one after another.
+var tables = []string{"customers", "products", "orders", "order_products"}
+
+var errRollback = errors.New("rollback")
+
+type Schema struct {
+	Source string
+	DSN    string
+	Diff   string
+}
+
+func (s Schema) Labels() []utils.Label {
+	labels := []utils.Label{
+		{Name: "Schema Source", Value: s.Source},
+	}
+	if s.Diff != "" {
+		labels = append(labels, utils.Label{Name: "Schema Diff", Value: s.Diff})
+	}
+
+	return labels
+}
+
+// Start brings the database back to its baseline with the isolation strategy selected by -isolation and
+// prepares the schema source selected by -schema. Invalid flags are rejected before anything is written.
+// With -list it writes nothing and returns the DSN as it is, so listing operations never changes the database.
+func Start(ctx context.Context, config utils.Config) (Schema, error) {
+	if err := config.Validate(); err != nil {
+		return Schema{}, err
+	}
+
+	if config.List {
+		return Schema{Source: config.Schema, DSN: config.DSN}, nil
+	}
+
+	if err := isolation.Apply(ctx, config.Isolation, config.DSN); err != nil {
+		return Schema{}, fmt.Errorf("failed to isolate database: %w", err)
+	}
+
+	return Prepare(ctx, config)
+}
+
+// Prepare builds the tables for the schema source selected by -schema and returns the DSN the benchmark must use.
+// Every connection it opens goes through the driver selected by -driver.
+//
+// The migrations source uses the tables in the public schema as they are. The ent and gorm sources
+// create the tables in a PostgreSQL schema of the same name with Ent's Schema.Create or GORM's AutoMigrate,
+// copy the current rows into them and put that schema first in the search_path. The DDL each ORM would run
+// against the migrated tables is saved to the results directory.
+func Prepare(ctx context.Context, config utils.Config) (Schema, error) {
+	source, dsn := config.Schema, config.DSN
+
+	var diff func(context.Context, *sql.DB) ([]byte, error)
+	var create func(context.Context, *sql.DB) error
+
+	switch source {
+	case "migrations":
+		return Schema{Source: source, DSN: dsn}, nil
+	case "ent":
+		diff, create = entDiff, entCreate
+	case "gorm":
+		diff, create = gormDiff, gormCreate
+	default:
+		return Schema{}, fmt.Errorf("unknown schema source \"%s\"", source)
+	}
+
+	db, err := config.OpenDB(dsn)
+	if err != nil {
+		return Schema{}, err
+	}
+	defer db.Close()
+
+	ddl, err := diff(ctx, db)
+	if err != nil {
+		return Schema{}, fmt.Errorf("failed to diff %s schema: %w", source, err)
+	}
+
+	if err := os.MkdirAll(config.Results, 0o755); err != nil {
+		return Schema{}, err
+	}
+
+	path := filepath.Join(config.Results, source+"-schema.diff.sql")
+	if err := os.WriteFile(path, ddl, 0o644); err != nil {
+		return Schema{}, err
+	}
+
+	if _, err := db.ExecContext(
+		ctx,
+		fmt.Sprintf("DROP SCHEMA IF EXISTS %[1]s CASCADE; CREATE SCHEMA %[1]s", pq.QuoteIdentifier(source)),
+	); err != nil {
+		return Schema{}, err
+	}
+
+	// Ent and GORM create their tables in the first schema of the search_path. The shared bookkeeping
+	// tables are always addressed as public.benchmark_schema_migrations and public.dataset_metadata, so no
+	// unqualified name can resolve to a copy in this schema.
+	schemaDSN := utils.WithDSNValue(dsn, "search_path", source+",public")
+
+	schemaDB, err := config.OpenDB(schemaDSN)
+	if err != nil {
+		return Schema{}, err
+	}
+	defer schemaDB.Close()
+
+	if err := create(ctx, schemaDB); err != nil {
+		return Schema{}, fmt.Errorf("failed to create %s schema: %w", source, err)
+	}
+
+	if err := copyRows(ctx, schemaDB, source); err != nil {
+		return Schema{}, fmt.Errorf("failed to copy rows into %s schema: %w", source, err)
+	}
+
+	return Schema{
+		Source: source,
+		DSN:    schemaDSN,
+		Diff:   path,
+	}, nil
+}
+
+func entDiff(ctx context.Context, db *sql.DB) ([]byte, error) {
+	var buf bytes.Buffer
+	err := migrate.
+		NewSchema(entsql.OpenDB(dialect.Postgres, db)).
+		WriteTo(ctx, &buf)
+
+	return buf.Bytes(), err
+}
+
+func entCreate(ctx context.Context, db *sql.DB) error {
+	return migrate.
+		NewSchema(entsql.OpenDB(dialect.Postgres, db)).
+		Create(ctx)
+}
+
+// gormDiff runs AutoMigrate against the migrated tables inside a transaction that is rolled back,
+// recording the DDL statements it emits on the way.
+func gormDiff(ctx context.Context, db *sql.DB) ([]byte, error) {
+	recorder := &ddlRecorder{}
+
+	gormDB, err := openGORM(db, recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gormDB.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(gormModels()...); err != nil {
+				return err
+			}
+
+			return errRollback
+		})
+	if !errors.Is(err, errRollback) {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, statement := range recorder.statements {
+		buf.WriteString(statement)
+		buf.WriteString(";\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func gormCreate(ctx context.Context, db *sql.DB) error {
+	gormDB, err := openGORM(db, &ddlRecorder{})
+	if err != nil {
+		return err
+	}
+
+	return gormDB.
+		WithContext(ctx).
+		AutoMigrate(gormModels()...)
+}
+
+func openGORM(db *sql.DB, recorder *ddlRecorder) (*gorm.DB, error) {
+	return gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{
+		Logger: recorder,
+	})
+}
+
+func gormModels() []any {
+	return []any{
+		&models.Customer{},
+		&models.Product{},
+		&models.Order{},
+		&models.OrderProduct{},
+	}
+}
+
+// copyRows copies the columns the migrated tables and the new tables have in common.
+func copyRows(ctx context.Context, db *sql.DB, source string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		var columns []string
+		if err := tx.
+			QueryRowContext(
+				ctx,
+				`SELECT array_agg(quote_ident(target.column_name::text) ORDER BY target.ordinal_position)
+                FROM information_schema.columns target
+                JOIN information_schema.columns origin
+                    ON origin.table_name = target.table_name AND origin.column_name = target.column_name
+                WHERE target.table_schema = $1 AND origin.table_schema = 'public' AND target.table_name = $2`,
+				source,
+				table,
+			).
+			Scan(pq.Array(&columns)); err != nil {
+			return err
+		}
+
+		list := strings.Join(columns, ", ")
+		if _, err := tx.ExecContext(
+			ctx,
+			fmt.Sprintf(
+				"INSERT INTO %[1]s.%[2]s (%[3]s) SELECT %[3]s FROM public.%[2]s",
+				pq.QuoteIdentifier(source),
+				table,
+				list,
+			),
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := dataset.SyncSequences(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
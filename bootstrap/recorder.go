@@ -0,0 +1,36 @@
+package bootstrap
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// ddlRecorder is a GORM logger that keeps the DDL statements and discards everything else.
+type ddlRecorder struct {
+	statements []string
+}
+
+func (r *ddlRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *ddlRecorder) Info(context.Context, string, ...any) {}
+
+func (r *ddlRecorder) Warn(context.Context, string, ...any) {}
+
+func (r *ddlRecorder) Error(context.Context, string, ...any) {}
+
+func (r *ddlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), err error) {
+	if err != nil {
+		return
+	}
+
+	statement, _ := fc()
+	switch strings.ToUpper(strings.SplitN(strings.TrimSpace(statement), " ", 2)[0]) {
+	case "CREATE", "ALTER", "DROP", "COMMENT":
+		r.statements = append(r.statements, statement)
+	}
+}
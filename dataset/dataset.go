@@ -0,0 +1,400 @@
+package dataset
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+const (
+	customersPerScale = 1000
+	productsPerScale  = 500
+
+	meanOrdersPerCustomer = 5
+	meanItemsPerOrder     = 2.5
+	maxItemsPerOrder      = 10
+	meanQuantity          = 1.5
+	maxQuantity           = 5
+	maxStock              = 500
+
+	nullDescriptionRatio = 0.1
+	productPopularityS   = 1.2
+
+	historyDays = 730
+)
+
+const (
+	productsStream = iota + 1
+	customersStream
+	ordersStream
+)
+
+// epoch anchors every generated timestamp so that a seed always produces the same rows.
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type Options struct {
+	Scale float64
+	Seed  uint64
+}
+
+type Metadata struct {
+	Scale         float64
+	Seed          uint64
+	Customers     int
+	Products      int
+	Orders        int
+	OrderProducts int
+	GeneratedAt   time.Time
+}
+
+func (m Metadata) Labels() []utils.Label {
+	scale := "unknown"
+	if m.Scale > 0 {
+		scale = fmt.Sprintf("%g (seed %d)", m.Scale, m.Seed)
+	}
+
+	return []utils.Label{
+		{Name: "Dataset Scale", Value: scale},
+		{Name: "Dataset Rows", Value: fmt.Sprintf(
+			"customers=%d products=%d orders=%d order_products=%d",
+			m.Customers,
+			m.Products,
+			m.Orders,
+			m.OrderProducts,
+		)},
+	}
+}
+
+type product struct {
+	description bool
+	price       int64
+	stock       int
+	createdAt   time.Time
+}
+
+type item struct {
+	productID int
+	quantity  int
+	price     int64
+}
+
+// Load replaces the contents of every table with a dataset generated for the given options.
+// The whole load runs in one transaction, so a failed load leaves the previous dataset in place.
+func Load(ctx context.Context, db *sql.DB, options Options) (Metadata, error) {
+	if options.Scale <= 0 {
+		return Metadata{}, fmt.Errorf("scale must be positive, got %g", options.Scale)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"TRUNCATE order_products, orders, products, customers RESTART IDENTITY",
+	); err != nil {
+		return Metadata{}, err
+	}
+
+	g := newGenerator(options)
+
+	metadata := Metadata{
+		Scale: options.Scale,
+		Seed:  options.Seed,
+	}
+
+	if metadata.Products, err = copyIn(ctx, tx, g.products, "products", "id", "name", "description", "price", "stock", "created_at"); err != nil {
+		return Metadata{}, err
+	}
+
+	if metadata.Customers, err = copyIn(ctx, tx, g.customers, "customers", "id", "name", "email", "created_at"); err != nil {
+		return Metadata{}, err
+	}
+
+	if metadata.Orders, err = copyIn(ctx, tx, g.orders, "orders", "id", "customer_id", "date", "total", "created_at"); err != nil {
+		return Metadata{}, err
+	}
+
+	if metadata.OrderProducts, err = copyIn(ctx, tx, g.orderProducts, "order_products", "id", "order_id", "product_id", "quantity", "price"); err != nil {
+		return Metadata{}, err
+	}
+
+	if err := SyncSequences(ctx, tx); err != nil {
+		return Metadata{}, err
+	}
+
+	if err := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO public.dataset_metadata (id, scale, seed, customers, products, orders, order_products)
+        VALUES (1, $1, $2, $3, $4, $5, $6)
+        ON CONFLICT (id) DO UPDATE SET
+            scale = EXCLUDED.scale,
+            seed = EXCLUDED.seed,
+            customers = EXCLUDED.customers,
+            products = EXCLUDED.products,
+            orders = EXCLUDED.orders,
+            order_products = EXCLUDED.order_products,
+            generated_at = CURRENT_TIMESTAMP
+        RETURNING generated_at`,
+		metadata.Scale,
+		int64(metadata.Seed),
+		metadata.Customers,
+		metadata.Products,
+		metadata.Orders,
+		metadata.OrderProducts,
+	).Scan(&metadata.GeneratedAt); err != nil {
+		return Metadata{}, err
+	}
+
+	return metadata, tx.Commit()
+}
+
+// Describe returns the metadata of the loaded dataset. Databases that were only seeded
+// by the migrations have no metadata row, so their row counts are taken from the tables.
+func Describe(ctx context.Context, db *sql.DB) (Metadata, error) {
+	var metadata Metadata
+	var seed int64
+	err := db.
+		QueryRowContext(
+			ctx,
+			"SELECT scale, seed, customers, products, orders, order_products, generated_at FROM public.dataset_metadata WHERE id = 1",
+		).
+		Scan(
+			&metadata.Scale,
+			&seed,
+			&metadata.Customers,
+			&metadata.Products,
+			&metadata.Orders,
+			&metadata.OrderProducts,
+			&metadata.GeneratedAt,
+		)
+	if err == nil {
+		metadata.Seed = uint64(seed)
+		return metadata, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return Metadata{}, err
+	}
+
+	return metadata, db.
+		QueryRowContext(
+			ctx,
+			`SELECT
+            (SELECT COUNT(*) FROM customers),
+            (SELECT COUNT(*) FROM products),
+            (SELECT COUNT(*) FROM orders),
+            (SELECT COUNT(*) FROM order_products)`,
+		).
+		Scan(
+			&metadata.Customers,
+			&metadata.Products,
+			&metadata.Orders,
+			&metadata.OrderProducts,
+		)
+}
+
+// SyncSequences moves the ID sequences past the rows that were inserted with explicit IDs.
+func SyncSequences(ctx context.Context, tx *sql.Tx) error {
+	for _, table := range []string{"customers", "products", "orders", "order_products"} {
+		if _, err := tx.ExecContext(
+			ctx,
+			fmt.Sprintf(
+				"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE(MAX(id), 1), MAX(id) IS NOT NULL) FROM %[1]s",
+				table,
+			),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyIn(ctx context.Context, tx *sql.Tx, rows func(func(...any) error) error, table string, columns ...string) (int, error) {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	count := 0
+	if err := rows(func(values ...any) error {
+		count++
+		_, err := stmt.ExecContext(ctx, values...)
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to copy %s: %w", table, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return 0, fmt.Errorf("failed to copy %s: %w", table, err)
+	}
+
+	return count, nil
+}
+
+type generator struct {
+	options       Options
+	customerCount int
+	catalog       []product
+}
+
+func newGenerator(options Options) *generator {
+	g := &generator{
+		options:       options,
+		customerCount: max(int(math.Round(customersPerScale*options.Scale)), 1),
+		catalog:       make([]product, max(int(math.Round(productsPerScale*options.Scale)), 1)),
+	}
+
+	r := g.rand(productsStream)
+	for i := range g.catalog {
+		// Prices are log-normal, so there are many cheap products and a long tail of expensive ones.
+		price := math.Exp(3.5 + 1.2*r.NormFloat64())
+
+		g.catalog[i] = product{
+			description: r.Float64() >= nullDescriptionRatio,
+			price:       max(min(int64(math.Round(price*100)), 999999), 99),
+			stock:       r.IntN(maxStock + 1),
+			createdAt:   g.timestamp(r),
+		}
+	}
+
+	return g
+}
+
+func (g *generator) rand(stream uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(g.options.Seed, stream))
+}
+
+func (g *generator) products(emit func(...any) error) error {
+	for i, p := range g.catalog {
+		var description any
+		if p.description {
+			description = fmt.Sprintf("Description of product %d", i+1)
+		}
+
+		if err := emit(
+			i+1,
+			fmt.Sprintf("Product %d", i+1),
+			description,
+			money(p.price),
+			p.stock,
+			p.createdAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) customers(emit func(...any) error) error {
+	r := g.rand(customersStream)
+	for i := range g.customerCount {
+		if err := emit(
+			i+1,
+			fmt.Sprintf("Customer %d", i+1),
+			fmt.Sprintf("customer_%d@example.com", i+1),
+			g.timestamp(r),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) orders(emit func(...any) error) error {
+	return g.walkOrders(func(orderID, customerID int, date time.Time, items []item) error {
+		var total int64
+		for _, it := range items {
+			total += it.price * int64(it.quantity)
+		}
+
+		return emit(orderID, customerID, date, money(total), date)
+	})
+}
+
+func (g *generator) orderProducts(emit func(...any) error) error {
+	id := 0
+	return g.walkOrders(func(orderID, _ int, _ time.Time, items []item) error {
+		for _, it := range items {
+			id++
+			if err := emit(id, orderID, it.productID, it.quantity, money(it.price)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// walkOrders generates the orders with their line items. It restarts the stream on every call,
+// which lets orders and order_products be copied one after another from the same sequence.
+func (g *generator) walkOrders(visit func(orderID, customerID int, date time.Time, items []item) error) error {
+	r := g.rand(ordersStream)
+
+	// Product popularity follows a Zipf law over a shuffled catalog,
+	// so best sellers are spread over the whole ID range.
+	popularity := r.Perm(len(g.catalog))
+	zipf := rand.NewZipf(r, productPopularityS, 1, uint64(len(g.catalog)-1))
+
+	orderID := 0
+	items := make([]item, 0, maxItemsPerOrder)
+	for customerID := 1; customerID <= g.customerCount; customerID++ {
+		orders := int(r.ExpFloat64() * meanOrdersPerCustomer)
+		for range orders {
+			orderID++
+			date := g.timestamp(r)
+
+			items = items[:0]
+			count := min(1+int(r.ExpFloat64()*(meanItemsPerOrder-1)), maxItemsPerOrder, len(g.catalog))
+			for len(items) < count {
+				productID := popularity[zipf.Uint64()] + 1
+				if containsProduct(items, productID) {
+					continue
+				}
+
+				items = append(items, item{
+					productID: productID,
+					quantity:  min(1+int(r.ExpFloat64()*(meanQuantity-1)), maxQuantity),
+					price:     g.catalog[productID-1].price,
+				})
+			}
+
+			if err := visit(orderID, customerID, date, items); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) timestamp(r *rand.Rand) time.Time {
+	return epoch.Add(-time.Duration(r.Int64N(historyDays*24*60*60)) * time.Second)
+}
+
+func containsProduct(items []item, productID int) bool {
+	for _, it := range items {
+		if it.productID == productID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// money formats an amount in cents as an exact NUMERIC literal.
+func money(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,57 @@
+package dataset
+
+import (
+	"context"
+	"database/sql"
+)
+
+// The delete operations remove a customer with HistoryOrders orders of HistoryLines order products each,
+// created for every iteration outside the timed section, and report the WAL written by the delete next to
+// its latency.
+const (
+	// HistoryOrders is the number of orders of every customer the delete operations remove.
+	HistoryOrders = 200
+	// HistoryLines is the number of order products of every one of those orders.
+	HistoryLines = 5
+	// HistoryRows is the number of rows a delete removes: the customer, their orders and the order products.
+	HistoryRows = 1 + HistoryOrders*(1+HistoryLines)
+)
+
+// History is a customer created by CreateCustomerHistory with the range of IDs of their orders.
+type History struct {
+	CustomerID   int
+	FirstOrderID int
+	LastOrderID  int
+}
+
+// CreateCustomerHistory inserts a customer with HistoryOrders orders, each with one line for every one
+// of the first HistoryLines products, in a single statement. It prepares the rows the delete operations remove,
+// identically for every implementation.
+func CreateCustomerHistory(ctx context.Context, db *sql.DB, name, email string, quantity int) (History, error) {
+	var h History
+	err := db.
+		QueryRowContext(
+			ctx,
+			`WITH c AS (
+                INSERT INTO customers (name, email) VALUES ($1, $2) RETURNING id
+            ), p AS (
+                SELECT id, price FROM products ORDER BY id LIMIT $3
+            ), o AS (
+                INSERT INTO orders (customer_id, total)
+                SELECT c.id, (SELECT SUM(price) FROM p) * $4 FROM c, generate_series(1, $5)
+                RETURNING id
+            ), l AS (
+                INSERT INTO order_products (order_id, product_id, quantity, price)
+                SELECT o.id, p.id, $4, p.price FROM o, p
+            )
+            SELECT (SELECT id FROM c), (SELECT MIN(id) FROM o), (SELECT MAX(id) FROM o)`,
+			name,
+			email,
+			HistoryLines,
+			quantity,
+			HistoryOrders,
+		).
+		Scan(&h.CustomerID, &h.FirstOrderID, &h.LastOrderID)
+
+	return h, err
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// CreateProductsInBulk imports products with CreateBulk, one statement per BatchSize builders,
+// all in one transaction.
+type CreateProductsInBulk struct {
+	BatchSize int
+	CreateProduct
+}
+
+func (op *CreateProductsInBulk) Name() string {
+	return "Create Products in Bulk"
+}
+
+func (op *CreateProductsInBulk) Rows() int {
+	return utils.BulkProducts
+}
+
+// BeforeEach deletes the products of the previous iteration.
+func (op *CreateProductsInBulk) BeforeEach(int) error {
+	return op.Teardown()
+}
+
+func (op *CreateProductsInBulk) Execute(iteration int) error {
+	ctx := context.Background()
+
+	tx, err := op.client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < utils.BulkProducts; start += op.BatchSize {
+		end := min(start+op.BatchSize, utils.BulkProducts)
+
+		builders := make([]*ent.ProductCreate, 0, end-start)
+		for i := start; i < end; i++ {
+			builders = append(builders, tx.Product.
+				Create().
+				SetName(fmt.Sprintf("Product_%d_%d", iteration, i)).
+				SetDescription("Test product").
+				SetPrice(productPrice).
+				SetStock(100),
+			)
+		}
+
+		if err := tx.Product.CreateBulk(builders...).Exec(ctx); err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	return tx.Commit()
+}
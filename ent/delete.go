@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// customerDelete is embedded by the Ent delete operations. The history is created and checked with plain SQL
+// on the database Ent opened.
+type customerDelete struct {
+	history dataset.History
+	utils.WALVolume
+	Ent
+}
+
+func (d *customerDelete) BeforeEach(iteration int) error {
+	ctx := context.Background()
+
+	var err error
+	d.history, err = dataset.CreateCustomerHistory(
+		ctx,
+		d.db,
+		fmt.Sprintf("Customer_%d", iteration),
+		fmt.Sprintf("customer_%d@history.test", iteration),
+		orderQuantity,
+	)
+	if err != nil {
+		return err
+	}
+
+	return d.Start(ctx, d.db)
+}
+
+func (d *customerDelete) Rows() int {
+	return dataset.HistoryRows
+}
+
+func (d *customerDelete) Check(int) error {
+	return reference.CheckCustomerDeleted(
+		context.Background(),
+		d.db,
+		d.history.CustomerID,
+		d.history.FirstOrderID,
+		d.history.LastOrderID,
+	)
+}
+
+func (d *customerDelete) AfterEach(int) error {
+	return d.Stop(context.Background(), d.db)
+}
+
+// DeleteCustomerWithOrdersCascade deletes only the customer and leaves the orders and their lines to the
+// ON DELETE CASCADE foreign keys the edge annotations declare.
+type DeleteCustomerWithOrdersCascade struct {
+	customerDelete
+}
+
+func (op *DeleteCustomerWithOrdersCascade) Name() string {
+	return "Delete Customer with Orders (Cascade)"
+}
+
+func (op *DeleteCustomerWithOrdersCascade) Execute(int) error {
+	return op.client.Customer.
+		DeleteOneID(op.history.CustomerID).
+		Exec(context.Background())
+}
+
+// DeleteCustomerWithOrdersChildrenFirst deletes the order products, the orders and then the customer in one
+// transaction, so the cascades find nothing left to delete.
+type DeleteCustomerWithOrdersChildrenFirst struct {
+	customerDelete
+}
+
+func (op *DeleteCustomerWithOrdersChildrenFirst) Name() string {
+	return "Delete Customer with Orders (Children First)"
+}
+
+func (op *DeleteCustomerWithOrdersChildrenFirst) Execute(int) error {
+	ctx := context.Background()
+
+	tx, err := op.client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.OrderProduct.
+		Delete().
+		Where(orderproduct.HasOrderWith(order.CustomerID(op.history.CustomerID))).
+		Exec(ctx); err != nil {
+		return rollback(tx, err)
+	}
+
+	if _, err := tx.Order.
+		Delete().
+		Where(order.CustomerID(op.history.CustomerID)).
+		Exec(ctx); err != nil {
+		return rollback(tx, err)
+	}
+
+	if err := tx.Customer.
+		DeleteOneID(op.history.CustomerID).
+		Exec(ctx); err != nil {
+		return rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
@@ -5,23 +5,36 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	"entgo.io/ent/dialect"
 	entsql "entgo.io/ent/dialect/sql"
-	_ "github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/bootstrap"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
 	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/customer"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/migrations"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
 )
 
+const (
+	productPrice  models.Money = 9999
+	orderQuantity              = 2
+)
+
 type Ent struct {
 	client *ent.Client
 	db     *sql.DB
 }
 
 type CreateProduct struct {
+	lastID int
 	Ent
 }
 
@@ -34,15 +47,37 @@ func (op *CreateProduct) Execute(iteration int) error {
 		Create().
 		SetName(fmt.Sprintf("Product_%d", iteration)).
 		SetDescription("Test product").
-		SetPrice(99.99).
+		SetPrice(productPrice).
 		SetStock(100).
 		Save(context.Background())
 
 	return err
 }
 
+func (op *CreateProduct) Setup() (err error) {
+	op.lastID, err = lastID(
+		op.client.Product.
+			Query().
+			Order(ent.Desc(product.FieldID)).
+			FirstID(context.Background()),
+	)
+
+	return err
+}
+
+func (op *CreateProduct) Teardown() error {
+	_, err := op.client.Product.
+		Delete().
+		Where(product.IDGT(op.lastID)).
+		Exec(context.Background())
+
+	return err
+}
+
 type GetCustomerByID struct {
-	ID int
+	IDs    utils.Generator
+	id     int
+	result *ent.Customer
 	Ent
 }
 
@@ -50,18 +85,46 @@ func (op *GetCustomerByID) Name() string {
 	return "Get Customer by ID"
 }
 
-func (op *GetCustomerByID) Execute(int) error {
-	_, err := op.client.Customer.
+func (op *GetCustomerByID) Execute(int) (err error) {
+	op.id = op.IDs.Next()
+	op.result, err = op.client.Customer.
+		Query().
+		Where(customer.ID(op.id)).
+		Only(context.Background())
+
+	return err
+}
+
+func (op *GetCustomerByID) Check(int) error {
+	return reference.CheckCustomer(context.Background(), op.db, op.id, models.Customer{
+		ID:        op.result.ID,
+		Name:      op.result.Name,
+		Email:     op.result.Email,
+		CreatedAt: op.result.CreatedAt,
+	})
+}
+
+type GetProductByID struct {
+	IDs utils.Generator
+	Ent
+}
+
+func (op *GetProductByID) Name() string {
+	return "Get Product by ID"
+}
+
+func (op *GetProductByID) Execute(int) error {
+	_, err := op.client.Product.
 		Query().
-		Where(customer.ID(op.ID)).
+		Where(product.ID(op.IDs.Next())).
 		Only(context.Background())
 
 	return err
 }
 
 type UpdateProductByID struct {
-	ID    int
-	Price float64
+	IDs   utils.Generator
+	Price models.Money
 	Ent
 }
 
@@ -71,7 +134,7 @@ func (op *UpdateProductByID) Name() string {
 
 func (op *UpdateProductByID) Execute(int) error {
 	return op.client.Product.
-		UpdateOneID(op.ID).
+		UpdateOneID(op.IDs.Next()).
 		SetPrice(op.Price).
 		Exec(context.Background())
 }
@@ -84,6 +147,10 @@ func (op *DeleteProductByName) Name() string {
 	return "Delete Product by Name"
 }
 
+func (op *DeleteProductByName) BeforeEach(iteration int) error {
+	return (&CreateProduct{Ent: op.Ent}).Execute(iteration)
+}
+
 func (op *DeleteProductByName) Execute(iteration int) error {
 	_, err := op.client.Product.Delete().
 		Where(product.Name(fmt.Sprintf("Product_%d", iteration))).
@@ -93,8 +160,9 @@ func (op *DeleteProductByName) Execute(iteration int) error {
 }
 
 type CreateOrderWithProductsByCustomerID struct {
-	CustomerID int
-	ProductID  int
+	CustomerIDs utils.Generator
+	ProductIDs  utils.Generator
+	lastID      int
 	Ent
 }
 
@@ -110,9 +178,9 @@ func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
 
 	o, err := tx.Order.
 		Create().
-		SetCustomerID(op.CustomerID).
+		SetCustomerID(op.CustomerIDs.Next()).
 		SetDate(time.Now()).
-		SetTotal(199.98).
+		SetTotal(productPrice.Mul(orderQuantity)).
 		Save(context.Background())
 	if err != nil {
 		return rollback(tx, err)
@@ -121,9 +189,9 @@ func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
 	_, err = tx.OrderProduct.
 		Create().
 		SetOrderID(o.ID).
-		SetProductID(op.ProductID).
-		SetQuantity(2).
-		SetPrice(99.99).
+		SetProductID(op.ProductIDs.Next()).
+		SetQuantity(orderQuantity).
+		SetPrice(productPrice).
 		Save(context.Background())
 	if err != nil {
 		return rollback(tx, err)
@@ -132,6 +200,35 @@ func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
 	return tx.Commit()
 }
 
+func (op *CreateOrderWithProductsByCustomerID) Setup() (err error) {
+	op.lastID, err = lastID(
+		op.client.Order.
+			Query().
+			Order(ent.Desc(order.FieldID)).
+			FirstID(context.Background()),
+	)
+
+	return err
+}
+
+func (op *CreateOrderWithProductsByCustomerID) Teardown() error {
+	_, err := op.client.Order.
+		Delete().
+		Where(order.IDGT(op.lastID)).
+		Exec(context.Background())
+
+	return err
+}
+
+// lastID returns the ID found by a FirstID query, or 0 when the table is empty.
+func lastID(id int, err error) (int, error) {
+	if ent.IsNotFound(err) {
+		return 0, nil
+	}
+
+	return id, err
+}
+
 func rollback(tx *ent.Tx, err error) error {
 	if rerr := tx.Rollback(); rerr != nil {
 		err = fmt.Errorf("%w: %v", err, rerr)
@@ -141,7 +238,9 @@ func rollback(tx *ent.Tx, err error) error {
 }
 
 type GetCustomerStatsByID struct {
-	CustomerID int
+	CustomerIDs utils.Generator
+	customerID  int
+	result      reference.CustomerStats
 	Ent
 }
 
@@ -150,30 +249,39 @@ func (op *GetCustomerStatsByID) Name() string {
 }
 
 func (op *GetCustomerStatsByID) Execute(int) error {
-	rows, err := op.db.
-		QueryContext(
-			context.Background(),
-			"SELECT COUNT(*) AS total_orders, COALESCE(SUM(total), 0) AS total_spent FROM orders WHERE customer_id = $1",
-			op.CustomerID,
-		)
-	if err != nil {
+	op.customerID = op.CustomerIDs.Next()
+
+	var results []reference.CustomerStats
+	if err := op.client.Order.
+		Query().
+		Where(order.CustomerID(op.customerID)).
+		Aggregate(
+			ent.As(ent.Count(), "total_orders"),
+			sumOrZero(order.FieldTotal, "total_spent"),
+		).
+		Scan(context.Background(), &results); err != nil {
 		return err
 	}
-	defer rows.Close()
 
-	if rows.Next() {
-		var totalOrders int64
-		var totalSpent float64
-		if err := rows.Scan(&totalOrders, &totalSpent); err != nil {
-			return err
-		}
+	op.result = results[0]
+
+	return nil
+}
+
+// sumOrZero aggregates SUM(column) AS alias, with 0 instead of NULL when no row matches.
+func sumOrZero(column, alias string) ent.AggregateFunc {
+	return func(s *entsql.Selector) string {
+		return entsql.As(fmt.Sprintf("COALESCE(%s, 0)", entsql.Sum(s.C(column))), alias)
 	}
+}
 
-	return rows.Err()
+func (op *GetCustomerStatsByID) Check(int) error {
+	return reference.CheckCustomerStats(context.Background(), op.db, op.customerID, op.result)
 }
 
 type GetProductSalesByLimit struct {
-	Limit int
+	Limit   int
+	results []reference.ProductSales
 	Ent
 }
 
@@ -182,47 +290,39 @@ func (op *GetProductSalesByLimit) Name() string {
 }
 
 func (op *GetProductSalesByLimit) Execute(int) error {
-	rows, err := op.db.
-		QueryContext(
-			context.Background(),
-			`SELECT 
-            p.id, 
-            p.name, 
-            SUM(op.quantity) AS total_sales, 
-            SUM(op.price * op.quantity) AS revenue
-        FROM order_products op
-        JOIN products p ON p.id = op.product_id
-        GROUP BY p.id, p.name
-        ORDER BY revenue DESC
-        LIMIT $1`,
-			op.Limit,
-		)
-	if err != nil {
-		return err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var productID int
-		var productName string
-		var totalSales int64
-		var revenue float64
-		if err := rows.Scan(
-			&productID,
-			&productName,
-			&totalSales,
-			&revenue,
-		); err != nil {
-			return err
-		}
-	}
+	op.results = op.results[:0]
+	return op.client.OrderProduct.
+		Query().
+		Modify(func(s *entsql.Selector) {
+			p := entsql.Table(product.Table)
+			s.Join(p).
+				On(s.C(orderproduct.ProductColumn), p.C(product.FieldID)).
+				Select(
+					entsql.As(s.C(orderproduct.ProductColumn), "product_id"),
+					entsql.As(p.C(product.FieldName), "product_name"),
+					entsql.As(entsql.Sum(s.C(orderproduct.FieldQuantity)), "total_sales"),
+					entsql.As(entsql.Sum(s.C(orderproduct.FieldPrice)+" * "+s.C(orderproduct.FieldQuantity)), "revenue"),
+				).
+				GroupBy(s.C(orderproduct.ProductColumn), p.C(product.FieldName)).
+				OrderBy(entsql.Desc("revenue")).
+				Limit(op.Limit)
+		}).
+		Scan(context.Background(), &op.results)
+}
 
-	return rows.Err()
+func (op *GetProductSalesByLimit) Check(int) error {
+	return reference.CheckProductSales(context.Background(), op.db, op.Limit, op.results)
 }
 
 func main() {
-	dsn := "host=localhost user= password= dbname= port=5432 sslmode=disable"
-	db, err := sql.Open("postgres", dsn)
+	config := utils.ParseConfig()
+
+	schema, err := bootstrap.Start(context.Background(), config)
+	if err != nil {
+		log.Fatalf("failed to prepare database: %v", err)
+	}
+
+	db, err := config.OpenDB(schema.DSN)
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
@@ -234,57 +334,243 @@ func main() {
 
 	ctx := context.Background()
 
-	customerEntity, err := client.Customer.
+	if err := migrations.Check(ctx, db); err != nil {
+		log.Fatalf("failed to check schema version: %v", err)
+	}
+
+	metadata, err := dataset.Describe(ctx, db)
+	if err != nil {
+		log.Fatalf("failed to describe dataset: %v", err)
+	}
+
+	customerIDs, err := client.Customer.
 		Query().
-		First(ctx)
+		Order(ent.Asc(customer.FieldID)).
+		IDs(ctx)
 	if err != nil {
-		log.Fatalf("failed to get customer: %v", err)
+		log.Fatalf("failed to get customers: %v", err)
 	}
 
-	productEntity, err := client.Product.
+	productIDs, err := client.Product.
 		Query().
-		First(ctx)
+		Order(ent.Asc(product.FieldID)).
+		IDs(ctx)
 	if err != nil {
-		log.Fatalf("failed to get product: %v", err)
+		log.Fatalf("failed to get products: %v", err)
 	}
 
-	iterations := 10000
+	generator := func(ids []int, stream uint64) utils.Generator {
+		g, err := config.Generator(ids, stream)
+		if err != nil {
+			log.Fatalf("failed to create generator: %v", err)
+		}
+
+		return g
+	}
 
 	clients := Ent{
 		client: client,
 		db:     db,
 	}
 
+	// The graph operations count their queries on a client of their own, so the other operations
+	// run on the plain driver.
+	counter := &countingDriver{Driver: drv}
+	counted := Ent{
+		client: ent.NewClient(ent.Driver(counter)),
+		db:     db,
+	}
+
 	operations := []utils.Operation{
 		&CreateProduct{
 			Ent: clients,
 		},
 		&GetCustomerByID{
-			ID:  customerEntity.ID,
+			IDs: generator(customerIDs, 0),
+			Ent: clients,
+		},
+		&GetProductByID{
+			IDs: generator(productIDs, 1),
 			Ent: clients,
 		},
+		&GetProductByIDFloat64{
+			GetProductByID: GetProductByID{
+				IDs: generator(productIDs, 1),
+				Ent: clients,
+			},
+		},
+		&GetProductByIDNullString{
+			GetProductByID: GetProductByID{
+				IDs: generator(productIDs, 1),
+				Ent: clients,
+			},
+		},
+		&GetProductByIDCoalesce{
+			GetProductByID: GetProductByID{
+				IDs: generator(productIDs, 1),
+				Ent: clients,
+			},
+		},
 		&UpdateProductByID{
-			ID:    productEntity.ID,
+			IDs:   generator(productIDs, 1),
+			Price: 8999,
+			Ent:   clients,
+		},
+		&UpdateProductByIDFloat64{
+			IDs:   generator(productIDs, 1),
 			Price: 89.99,
 			Ent:   clients,
 		},
+		&UpdateProductByExpression{
+			IDs: generator(productIDs, 1),
+			productUpdate: productUpdate{
+				Ent: clients,
+			},
+		},
+		&UpdateProductsByIDs{
+			IDs: generator(productIDs, 1),
+			productUpdate: productUpdate{
+				Ent: clients,
+			},
+		},
+		&UpdateProductsByPriceBand{
+			productUpdate: productUpdate{
+				Ent: clients,
+			},
+		},
 		&CreateOrderWithProductsByCustomerID{
-			CustomerID: customerEntity.ID,
-			ProductID:  productEntity.ID,
-			Ent:        clients,
+			CustomerIDs: generator(customerIDs, 0),
+			ProductIDs:  generator(productIDs, 1),
+			Ent:         clients,
+		},
+		&CreateOrderWithProductsByCustomerIDFloat64{
+			CreateOrderWithProductsByCustomerID: CreateOrderWithProductsByCustomerID{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				Ent:         clients,
+			},
 		},
 		&GetCustomerStatsByID{
-			CustomerID: customerEntity.ID,
-			Ent:        clients,
+			CustomerIDs: generator(customerIDs, 0),
+			Ent:         clients,
+		},
+		&GetCustomerStatsByIDFloat64{
+			GetCustomerStatsByID: GetCustomerStatsByID{
+				CustomerIDs: generator(customerIDs, 0),
+				Ent:         clients,
+			},
+		},
+		&GetCustomerStatsByIDRaw{
+			GetCustomerStatsByID: GetCustomerStatsByID{
+				CustomerIDs: generator(customerIDs, 0),
+				Ent:         clients,
+			},
 		},
 		&GetProductSalesByLimit{
 			Limit: 10,
 			Ent:   clients,
 		},
+		&GetProductSalesByLimitFloat64{
+			GetProductSalesByLimit: GetProductSalesByLimit{
+				Limit: 10,
+				Ent:   clients,
+			},
+		},
+		&GetProductSalesByLimitRaw{
+			GetProductSalesByLimit: GetProductSalesByLimit{
+				Limit: 10,
+				Ent:   clients,
+			},
+		},
+		&LoadCustomerGraphNPlusOne{
+			customerGraph: customerGraph{
+				CustomerIDs: generator(customerIDs, 0),
+				Driver:      counter,
+				Ent:         counted,
+			},
+		},
+		&LoadCustomerGraphEager{
+			customerGraph: customerGraph{
+				CustomerIDs: generator(customerIDs, 0),
+				Driver:      counter,
+				Ent:         counted,
+			},
+		},
+		&PaginateProductsOffset{
+			productWalk: productWalk{
+				Ent: clients,
+			},
+		},
+		&PaginateProductsKeyset{
+			productWalk: productWalk{
+				Ent: clients,
+			},
+		},
+		&SearchProducts{
+			Filters: config.FilterGenerator(2),
+			Ent:     clients,
+		},
+		&StreamOrderProductsAll{
+			orderProductStream: orderProductStream{
+				OrderProductStream: reference.OrderProductStream{Limit: config.StreamRows},
+				Ent:                clients,
+			},
+		},
+		&StreamOrderProductsKeyset{
+			BatchSize: config.BatchSize,
+			orderProductStream: orderProductStream{
+				OrderProductStream: reference.OrderProductStream{Limit: config.StreamRows},
+				Ent:                clients,
+			},
+		},
+		&ReserveInventory{
+			CustomerIDs: generator(customerIDs, 0),
+			ProductIDs:  generator(productIDs, 1),
+			Workers:     config.Workers,
+			Ent:         clients,
+		},
+		&ReserveInventoryUnordered{
+			ReserveInventory: ReserveInventory{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				Workers:     config.Workers,
+				Ent:         clients,
+			},
+		},
+		&UpsertCustomerByEmail{
+			CustomerIDs:   generator(customerIDs, 0),
+			ConflictRatio: 0.1,
+			Ent:           clients,
+		},
+		&UpsertCustomerByEmailHighConflict{
+			UpsertCustomerByEmail: UpsertCustomerByEmail{
+				CustomerIDs:   generator(customerIDs, 0),
+				ConflictRatio: 0.9,
+				Ent:           clients,
+			},
+		},
 		&DeleteProductByName{
 			Ent: clients,
 		},
+		&DeleteCustomerWithOrdersCascade{
+			customerDelete: customerDelete{
+				Ent: clients,
+			},
+		},
+		&DeleteCustomerWithOrdersChildrenFirst{
+			customerDelete: customerDelete{
+				Ent: clients,
+			},
+		},
+		&CreateProductsInBulk{
+			BatchSize: config.BatchSize,
+			CreateProduct: CreateProduct{
+				Ent: clients,
+			},
+		},
 	}
 
-	utils.PrintResult(operations, iterations)
+	if err := config.Run(operations, slices.Concat(config.Labels(), schema.Labels(), metadata.Labels())...); err != nil {
+		log.Fatalf("failed to run operations: %v", err)
+	}
 }
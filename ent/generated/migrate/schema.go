@@ -25,7 +25,7 @@ var (
 	OrdersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "date", Type: field.TypeTime},
-		{Name: "total", Type: field.TypeFloat64},
+		{Name: "total", Type: field.TypeFloat64, SchemaType: map[string]string{"postgres": "numeric(10,2)"}},
 		{Name: "created_at", Type: field.TypeTime},
 		{Name: "customer_id", Type: field.TypeInt},
 	}
@@ -47,7 +47,7 @@ var (
 	OrderProductsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "quantity", Type: field.TypeInt},
-		{Name: "price", Type: field.TypeFloat64},
+		{Name: "price", Type: field.TypeFloat64, SchemaType: map[string]string{"postgres": "numeric(10,2)"}},
 		{Name: "order_id", Type: field.TypeInt},
 		{Name: "product_id", Type: field.TypeInt},
 	}
@@ -76,7 +76,7 @@ var (
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "name", Type: field.TypeString},
 		{Name: "description", Type: field.TypeString, Nullable: true},
-		{Name: "price", Type: field.TypeFloat64},
+		{Name: "price", Type: field.TypeFloat64, SchemaType: map[string]string{"postgres": "numeric(10,2)"}},
 		{Name: "stock", Type: field.TypeInt},
 		{Name: "created_at", Type: field.TypeTime},
 	}
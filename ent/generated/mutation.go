@@ -16,6 +16,7 @@ import (
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/predicate"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 const (
@@ -567,8 +568,8 @@ type OrderMutation struct {
 	typ             string
 	id              *int
 	date            *time.Time
-	total           *float64
-	addtotal        *float64
+	total           *models.Money
+	addtotal        *models.Money
 	created_at      *time.Time
 	clearedFields   map[string]struct{}
 	customer        *int
@@ -752,13 +753,13 @@ func (m *OrderMutation) ResetDate() {
 }
 
 // SetTotal sets the "total" field.
-func (m *OrderMutation) SetTotal(f float64) {
-	m.total = &f
+func (m *OrderMutation) SetTotal(value models.Money) {
+	m.total = &value
 	m.addtotal = nil
 }
 
 // Total returns the value of the "total" field in the mutation.
-func (m *OrderMutation) Total() (r float64, exists bool) {
+func (m *OrderMutation) Total() (r models.Money, exists bool) {
 	v := m.total
 	if v == nil {
 		return
@@ -769,7 +770,7 @@ func (m *OrderMutation) Total() (r float64, exists bool) {
 // OldTotal returns the old "total" field's value of the Order entity.
 // If the Order object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *OrderMutation) OldTotal(ctx context.Context) (v float64, err error) {
+func (m *OrderMutation) OldTotal(ctx context.Context) (v models.Money, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldTotal is only allowed on UpdateOne operations")
 	}
@@ -783,17 +784,17 @@ func (m *OrderMutation) OldTotal(ctx context.Context) (v float64, err error) {
 	return oldValue.Total, nil
 }
 
-// AddTotal adds f to the "total" field.
-func (m *OrderMutation) AddTotal(f float64) {
+// AddTotal adds value to the "total" field.
+func (m *OrderMutation) AddTotal(value models.Money) {
 	if m.addtotal != nil {
-		*m.addtotal += f
+		*m.addtotal += value
 	} else {
-		m.addtotal = &f
+		m.addtotal = &value
 	}
 }
 
 // AddedTotal returns the value that was added to the "total" field in this mutation.
-func (m *OrderMutation) AddedTotal() (r float64, exists bool) {
+func (m *OrderMutation) AddedTotal() (r models.Money, exists bool) {
 	v := m.addtotal
 	if v == nil {
 		return
@@ -1028,7 +1029,7 @@ func (m *OrderMutation) SetField(name string, value ent.Value) error {
 		m.SetDate(v)
 		return nil
 	case order.FieldTotal:
-		v, ok := value.(float64)
+		v, ok := value.(models.Money)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -1072,7 +1073,7 @@ func (m *OrderMutation) AddedField(name string) (ent.Value, bool) {
 func (m *OrderMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case order.FieldTotal:
-		v, ok := value.(float64)
+		v, ok := value.(models.Money)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -1231,8 +1232,8 @@ type OrderProductMutation struct {
 	id             *int
 	quantity       *int
 	addquantity    *int
-	price          *float64
-	addprice       *float64
+	price          *models.Money
+	addprice       *models.Money
 	clearedFields  map[string]struct{}
 	_order         *int
 	cleared_order  bool
@@ -1470,13 +1471,13 @@ func (m *OrderProductMutation) ResetQuantity() {
 }
 
 // SetPrice sets the "price" field.
-func (m *OrderProductMutation) SetPrice(f float64) {
-	m.price = &f
+func (m *OrderProductMutation) SetPrice(value models.Money) {
+	m.price = &value
 	m.addprice = nil
 }
 
 // Price returns the value of the "price" field in the mutation.
-func (m *OrderProductMutation) Price() (r float64, exists bool) {
+func (m *OrderProductMutation) Price() (r models.Money, exists bool) {
 	v := m.price
 	if v == nil {
 		return
@@ -1487,7 +1488,7 @@ func (m *OrderProductMutation) Price() (r float64, exists bool) {
 // OldPrice returns the old "price" field's value of the OrderProduct entity.
 // If the OrderProduct object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *OrderProductMutation) OldPrice(ctx context.Context) (v float64, err error) {
+func (m *OrderProductMutation) OldPrice(ctx context.Context) (v models.Money, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldPrice is only allowed on UpdateOne operations")
 	}
@@ -1501,17 +1502,17 @@ func (m *OrderProductMutation) OldPrice(ctx context.Context) (v float64, err err
 	return oldValue.Price, nil
 }
 
-// AddPrice adds f to the "price" field.
-func (m *OrderProductMutation) AddPrice(f float64) {
+// AddPrice adds value to the "price" field.
+func (m *OrderProductMutation) AddPrice(value models.Money) {
 	if m.addprice != nil {
-		*m.addprice += f
+		*m.addprice += value
 	} else {
-		m.addprice = &f
+		m.addprice = &value
 	}
 }
 
 // AddedPrice returns the value that was added to the "price" field in this mutation.
-func (m *OrderProductMutation) AddedPrice() (r float64, exists bool) {
+func (m *OrderProductMutation) AddedPrice() (r models.Money, exists bool) {
 	v := m.addprice
 	if v == nil {
 		return
@@ -1690,7 +1691,7 @@ func (m *OrderProductMutation) SetField(name string, value ent.Value) error {
 		m.SetQuantity(v)
 		return nil
 	case orderproduct.FieldPrice:
-		v, ok := value.(float64)
+		v, ok := value.(models.Money)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -1739,7 +1740,7 @@ func (m *OrderProductMutation) AddField(name string, value ent.Value) error {
 		m.AddQuantity(v)
 		return nil
 	case orderproduct.FieldPrice:
-		v, ok := value.(float64)
+		v, ok := value.(models.Money)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -1888,8 +1889,8 @@ type ProductMutation struct {
 	id                    *int
 	name                  *string
 	description           *string
-	price                 *float64
-	addprice              *float64
+	price                 *models.Money
+	addprice              *models.Money
 	stock                 *int
 	addstock              *int
 	created_at            *time.Time
@@ -2053,7 +2054,7 @@ func (m *ProductMutation) Description() (r string, exists bool) {
 // OldDescription returns the old "description" field's value of the Product entity.
 // If the Product object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *ProductMutation) OldDescription(ctx context.Context) (v string, err error) {
+func (m *ProductMutation) OldDescription(ctx context.Context) (v *string, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldDescription is only allowed on UpdateOne operations")
 	}
@@ -2086,13 +2087,13 @@ func (m *ProductMutation) ResetDescription() {
 }
 
 // SetPrice sets the "price" field.
-func (m *ProductMutation) SetPrice(f float64) {
-	m.price = &f
+func (m *ProductMutation) SetPrice(value models.Money) {
+	m.price = &value
 	m.addprice = nil
 }
 
 // Price returns the value of the "price" field in the mutation.
-func (m *ProductMutation) Price() (r float64, exists bool) {
+func (m *ProductMutation) Price() (r models.Money, exists bool) {
 	v := m.price
 	if v == nil {
 		return
@@ -2103,7 +2104,7 @@ func (m *ProductMutation) Price() (r float64, exists bool) {
 // OldPrice returns the old "price" field's value of the Product entity.
 // If the Product object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *ProductMutation) OldPrice(ctx context.Context) (v float64, err error) {
+func (m *ProductMutation) OldPrice(ctx context.Context) (v models.Money, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldPrice is only allowed on UpdateOne operations")
 	}
@@ -2117,17 +2118,17 @@ func (m *ProductMutation) OldPrice(ctx context.Context) (v float64, err error) {
 	return oldValue.Price, nil
 }
 
-// AddPrice adds f to the "price" field.
-func (m *ProductMutation) AddPrice(f float64) {
+// AddPrice adds value to the "price" field.
+func (m *ProductMutation) AddPrice(value models.Money) {
 	if m.addprice != nil {
-		*m.addprice += f
+		*m.addprice += value
 	} else {
-		m.addprice = &f
+		m.addprice = &value
 	}
 }
 
 // AddedPrice returns the value that was added to the "price" field in this mutation.
-func (m *ProductMutation) AddedPrice() (r float64, exists bool) {
+func (m *ProductMutation) AddedPrice() (r models.Money, exists bool) {
 	v := m.addprice
 	if v == nil {
 		return
@@ -2398,7 +2399,7 @@ func (m *ProductMutation) SetField(name string, value ent.Value) error {
 		m.SetDescription(v)
 		return nil
 	case product.FieldPrice:
-		v, ok := value.(float64)
+		v, ok := value.(models.Money)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -2454,7 +2455,7 @@ func (m *ProductMutation) AddedField(name string) (ent.Value, bool) {
 func (m *ProductMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	case product.FieldPrice:
-		v, ok := value.(float64)
+		v, ok := value.(models.Money)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
@@ -11,6 +11,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/customer"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // Order is the model entity for the Order schema.
@@ -23,7 +24,7 @@ type Order struct {
 	// Date holds the value of the "date" field.
 	Date time.Time `json:"date,omitempty"`
 	// Total holds the value of the "total" field.
-	Total float64 `json:"total,omitempty"`
+	Total models.Money `json:"total,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -69,7 +70,7 @@ func (*Order) scanValues(columns []string) ([]any, error) {
 	for i := range columns {
 		switch columns[i] {
 		case order.FieldTotal:
-			values[i] = new(sql.NullFloat64)
+			values[i] = new(models.Money)
 		case order.FieldID, order.FieldCustomerID:
 			values[i] = new(sql.NullInt64)
 		case order.FieldDate, order.FieldCreatedAt:
@@ -108,10 +109,10 @@ func (o *Order) assignValues(columns []string, values []any) error {
 				o.Date = value.Time
 			}
 		case order.FieldTotal:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*models.Money); !ok {
 				return fmt.Errorf("unexpected type %T for field total", values[i])
-			} else if value.Valid {
-				o.Total = value.Float64
+			} else if value != nil {
+				o.Total = *value
 			}
 		case order.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
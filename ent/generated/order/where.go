@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/predicate"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // ID filters vertices based on their ID field.
@@ -66,7 +67,7 @@ func Date(v time.Time) predicate.Order {
 }
 
 // Total applies equality check predicate on the "total" field. It's identical to TotalEQ.
-func Total(v float64) predicate.Order {
+func Total(v models.Money) predicate.Order {
 	return predicate.Order(sql.FieldEQ(FieldTotal, v))
 }
 
@@ -136,42 +137,42 @@ func DateLTE(v time.Time) predicate.Order {
 }
 
 // TotalEQ applies the EQ predicate on the "total" field.
-func TotalEQ(v float64) predicate.Order {
+func TotalEQ(v models.Money) predicate.Order {
 	return predicate.Order(sql.FieldEQ(FieldTotal, v))
 }
 
 // TotalNEQ applies the NEQ predicate on the "total" field.
-func TotalNEQ(v float64) predicate.Order {
+func TotalNEQ(v models.Money) predicate.Order {
 	return predicate.Order(sql.FieldNEQ(FieldTotal, v))
 }
 
 // TotalIn applies the In predicate on the "total" field.
-func TotalIn(vs ...float64) predicate.Order {
+func TotalIn(vs ...models.Money) predicate.Order {
 	return predicate.Order(sql.FieldIn(FieldTotal, vs...))
 }
 
 // TotalNotIn applies the NotIn predicate on the "total" field.
-func TotalNotIn(vs ...float64) predicate.Order {
+func TotalNotIn(vs ...models.Money) predicate.Order {
 	return predicate.Order(sql.FieldNotIn(FieldTotal, vs...))
 }
 
 // TotalGT applies the GT predicate on the "total" field.
-func TotalGT(v float64) predicate.Order {
+func TotalGT(v models.Money) predicate.Order {
 	return predicate.Order(sql.FieldGT(FieldTotal, v))
 }
 
 // TotalGTE applies the GTE predicate on the "total" field.
-func TotalGTE(v float64) predicate.Order {
+func TotalGTE(v models.Money) predicate.Order {
 	return predicate.Order(sql.FieldGTE(FieldTotal, v))
 }
 
 // TotalLT applies the LT predicate on the "total" field.
-func TotalLT(v float64) predicate.Order {
+func TotalLT(v models.Money) predicate.Order {
 	return predicate.Order(sql.FieldLT(FieldTotal, v))
 }
 
 // TotalLTE applies the LTE predicate on the "total" field.
-func TotalLTE(v float64) predicate.Order {
+func TotalLTE(v models.Money) predicate.Order {
 	return predicate.Order(sql.FieldLTE(FieldTotal, v))
 }
 
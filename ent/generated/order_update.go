@@ -15,13 +15,15 @@ import (
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/predicate"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // OrderUpdate is the builder for updating Order entities.
 type OrderUpdate struct {
 	config
-	hooks    []Hook
-	mutation *OrderMutation
+	hooks     []Hook
+	mutation  *OrderMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // Where appends a list predicates to the OrderUpdate builder.
@@ -59,23 +61,23 @@ func (ou *OrderUpdate) SetNillableDate(t *time.Time) *OrderUpdate {
 }
 
 // SetTotal sets the "total" field.
-func (ou *OrderUpdate) SetTotal(f float64) *OrderUpdate {
+func (ou *OrderUpdate) SetTotal(m models.Money) *OrderUpdate {
 	ou.mutation.ResetTotal()
-	ou.mutation.SetTotal(f)
+	ou.mutation.SetTotal(m)
 	return ou
 }
 
 // SetNillableTotal sets the "total" field if the given value is not nil.
-func (ou *OrderUpdate) SetNillableTotal(f *float64) *OrderUpdate {
-	if f != nil {
-		ou.SetTotal(*f)
+func (ou *OrderUpdate) SetNillableTotal(m *models.Money) *OrderUpdate {
+	if m != nil {
+		ou.SetTotal(*m)
 	}
 	return ou
 }
 
-// AddTotal adds f to the "total" field.
-func (ou *OrderUpdate) AddTotal(f float64) *OrderUpdate {
-	ou.mutation.AddTotal(f)
+// AddTotal adds m to the "total" field.
+func (ou *OrderUpdate) AddTotal(m models.Money) *OrderUpdate {
+	ou.mutation.AddTotal(m)
 	return ou
 }
 
@@ -166,6 +168,12 @@ func (ou *OrderUpdate) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (ou *OrderUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *OrderUpdate {
+	ou.modifiers = append(ou.modifiers, modifiers...)
+	return ou
+}
+
 func (ou *OrderUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if err := ou.check(); err != nil {
 		return n, err
@@ -261,6 +269,7 @@ func (ou *OrderUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(ou.modifiers...)
 	if n, err = sqlgraph.UpdateNodes(ctx, ou.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{order.Label}
@@ -276,9 +285,10 @@ func (ou *OrderUpdate) sqlSave(ctx context.Context) (n int, err error) {
 // OrderUpdateOne is the builder for updating a single Order entity.
 type OrderUpdateOne struct {
 	config
-	fields   []string
-	hooks    []Hook
-	mutation *OrderMutation
+	fields    []string
+	hooks     []Hook
+	mutation  *OrderMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // SetCustomerID sets the "customer_id" field.
@@ -310,23 +320,23 @@ func (ouo *OrderUpdateOne) SetNillableDate(t *time.Time) *OrderUpdateOne {
 }
 
 // SetTotal sets the "total" field.
-func (ouo *OrderUpdateOne) SetTotal(f float64) *OrderUpdateOne {
+func (ouo *OrderUpdateOne) SetTotal(m models.Money) *OrderUpdateOne {
 	ouo.mutation.ResetTotal()
-	ouo.mutation.SetTotal(f)
+	ouo.mutation.SetTotal(m)
 	return ouo
 }
 
 // SetNillableTotal sets the "total" field if the given value is not nil.
-func (ouo *OrderUpdateOne) SetNillableTotal(f *float64) *OrderUpdateOne {
-	if f != nil {
-		ouo.SetTotal(*f)
+func (ouo *OrderUpdateOne) SetNillableTotal(m *models.Money) *OrderUpdateOne {
+	if m != nil {
+		ouo.SetTotal(*m)
 	}
 	return ouo
 }
 
-// AddTotal adds f to the "total" field.
-func (ouo *OrderUpdateOne) AddTotal(f float64) *OrderUpdateOne {
-	ouo.mutation.AddTotal(f)
+// AddTotal adds m to the "total" field.
+func (ouo *OrderUpdateOne) AddTotal(m models.Money) *OrderUpdateOne {
+	ouo.mutation.AddTotal(m)
 	return ouo
 }
 
@@ -430,6 +440,12 @@ func (ouo *OrderUpdateOne) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (ouo *OrderUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *OrderUpdateOne {
+	ouo.modifiers = append(ouo.modifiers, modifiers...)
+	return ouo
+}
+
 func (ouo *OrderUpdateOne) sqlSave(ctx context.Context) (_node *Order, err error) {
 	if err := ouo.check(); err != nil {
 		return _node, err
@@ -542,6 +558,7 @@ func (ouo *OrderUpdateOne) sqlSave(ctx context.Context) (_node *Order, err error
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(ouo.modifiers...)
 	_node = &Order{config: ouo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -11,6 +11,7 @@ import (
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // OrderProduct is the model entity for the OrderProduct schema.
@@ -25,7 +26,7 @@ type OrderProduct struct {
 	// Quantity holds the value of the "quantity" field.
 	Quantity int `json:"quantity,omitempty"`
 	// Price holds the value of the "price" field.
-	Price float64 `json:"price,omitempty"`
+	Price models.Money `json:"price,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the OrderProductQuery when eager-loading is set.
 	Edges        OrderProductEdges `json:"edges"`
@@ -71,7 +72,7 @@ func (*OrderProduct) scanValues(columns []string) ([]any, error) {
 	for i := range columns {
 		switch columns[i] {
 		case orderproduct.FieldPrice:
-			values[i] = new(sql.NullFloat64)
+			values[i] = new(models.Money)
 		case orderproduct.FieldID, orderproduct.FieldOrderID, orderproduct.FieldProductID, orderproduct.FieldQuantity:
 			values[i] = new(sql.NullInt64)
 		default:
@@ -114,10 +115,10 @@ func (op *OrderProduct) assignValues(columns []string, values []any) error {
 				op.Quantity = int(value.Int64)
 			}
 		case orderproduct.FieldPrice:
-			if value, ok := values[i].(*sql.NullFloat64); !ok {
+			if value, ok := values[i].(*models.Money); !ok {
 				return fmt.Errorf("unexpected type %T for field price", values[i])
-			} else if value.Valid {
-				op.Price = value.Float64
+			} else if value != nil {
+				op.Price = *value
 			}
 		default:
 			op.selectValues.Set(columns[i], values[i])
@@ -6,6 +6,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/predicate"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // ID filters vertices based on their ID field.
@@ -69,7 +70,7 @@ func Quantity(v int) predicate.OrderProduct {
 }
 
 // Price applies equality check predicate on the "price" field. It's identical to PriceEQ.
-func Price(v float64) predicate.OrderProduct {
+func Price(v models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldEQ(FieldPrice, v))
 }
 
@@ -154,42 +155,42 @@ func QuantityLTE(v int) predicate.OrderProduct {
 }
 
 // PriceEQ applies the EQ predicate on the "price" field.
-func PriceEQ(v float64) predicate.OrderProduct {
+func PriceEQ(v models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldEQ(FieldPrice, v))
 }
 
 // PriceNEQ applies the NEQ predicate on the "price" field.
-func PriceNEQ(v float64) predicate.OrderProduct {
+func PriceNEQ(v models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldNEQ(FieldPrice, v))
 }
 
 // PriceIn applies the In predicate on the "price" field.
-func PriceIn(vs ...float64) predicate.OrderProduct {
+func PriceIn(vs ...models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldIn(FieldPrice, vs...))
 }
 
 // PriceNotIn applies the NotIn predicate on the "price" field.
-func PriceNotIn(vs ...float64) predicate.OrderProduct {
+func PriceNotIn(vs ...models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldNotIn(FieldPrice, vs...))
 }
 
 // PriceGT applies the GT predicate on the "price" field.
-func PriceGT(v float64) predicate.OrderProduct {
+func PriceGT(v models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldGT(FieldPrice, v))
 }
 
 // PriceGTE applies the GTE predicate on the "price" field.
-func PriceGTE(v float64) predicate.OrderProduct {
+func PriceGTE(v models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldGTE(FieldPrice, v))
 }
 
 // PriceLT applies the LT predicate on the "price" field.
-func PriceLT(v float64) predicate.OrderProduct {
+func PriceLT(v models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldLT(FieldPrice, v))
 }
 
 // PriceLTE applies the LTE predicate on the "price" field.
-func PriceLTE(v float64) predicate.OrderProduct {
+func PriceLTE(v models.Money) predicate.OrderProduct {
 	return predicate.OrderProduct(sql.FieldLTE(FieldPrice, v))
 }
 
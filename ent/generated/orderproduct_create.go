@@ -7,11 +7,13 @@ import (
 	"errors"
 	"fmt"
 
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // OrderProductCreate is the builder for creating a OrderProduct entity.
@@ -19,6 +21,7 @@ type OrderProductCreate struct {
 	config
 	mutation *OrderProductMutation
 	hooks    []Hook
+	conflict []sql.ConflictOption
 }
 
 // SetOrderID sets the "order_id" field.
@@ -40,8 +43,8 @@ func (opc *OrderProductCreate) SetQuantity(i int) *OrderProductCreate {
 }
 
 // SetPrice sets the "price" field.
-func (opc *OrderProductCreate) SetPrice(f float64) *OrderProductCreate {
-	opc.mutation.SetPrice(f)
+func (opc *OrderProductCreate) SetPrice(m models.Money) *OrderProductCreate {
+	opc.mutation.SetPrice(m)
 	return opc
 }
 
@@ -133,6 +136,7 @@ func (opc *OrderProductCreate) createSpec() (*OrderProduct, *sqlgraph.CreateSpec
 		_node = &OrderProduct{config: opc.config}
 		_spec = sqlgraph.NewCreateSpec(orderproduct.Table, sqlgraph.NewFieldSpec(orderproduct.FieldID, field.TypeInt))
 	)
+	_spec.OnConflict = opc.conflict
 	if value, ok := opc.mutation.Quantity(); ok {
 		_spec.SetField(orderproduct.FieldQuantity, field.TypeInt, value)
 		_node.Quantity = value
@@ -178,11 +182,264 @@ func (opc *OrderProductCreate) createSpec() (*OrderProduct, *sqlgraph.CreateSpec
 	return _node, _spec
 }
 
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.OrderProduct.Create().
+//		SetOrderID(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.OrderProductUpsert) {
+//			SetOrderID(v+v).
+//		}).
+//		Exec(ctx)
+func (opc *OrderProductCreate) OnConflict(opts ...sql.ConflictOption) *OrderProductUpsertOne {
+	opc.conflict = opts
+	return &OrderProductUpsertOne{
+		create: opc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.OrderProduct.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (opc *OrderProductCreate) OnConflictColumns(columns ...string) *OrderProductUpsertOne {
+	opc.conflict = append(opc.conflict, sql.ConflictColumns(columns...))
+	return &OrderProductUpsertOne{
+		create: opc,
+	}
+}
+
+type (
+	// OrderProductUpsertOne is the builder for "upsert"-ing
+	//  one OrderProduct node.
+	OrderProductUpsertOne struct {
+		create *OrderProductCreate
+	}
+
+	// OrderProductUpsert is the "OnConflict" setter.
+	OrderProductUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetOrderID sets the "order_id" field.
+func (u *OrderProductUpsert) SetOrderID(v int) *OrderProductUpsert {
+	u.Set(orderproduct.FieldOrderID, v)
+	return u
+}
+
+// UpdateOrderID sets the "order_id" field to the value that was provided on create.
+func (u *OrderProductUpsert) UpdateOrderID() *OrderProductUpsert {
+	u.SetExcluded(orderproduct.FieldOrderID)
+	return u
+}
+
+// SetProductID sets the "product_id" field.
+func (u *OrderProductUpsert) SetProductID(v int) *OrderProductUpsert {
+	u.Set(orderproduct.FieldProductID, v)
+	return u
+}
+
+// UpdateProductID sets the "product_id" field to the value that was provided on create.
+func (u *OrderProductUpsert) UpdateProductID() *OrderProductUpsert {
+	u.SetExcluded(orderproduct.FieldProductID)
+	return u
+}
+
+// SetQuantity sets the "quantity" field.
+func (u *OrderProductUpsert) SetQuantity(v int) *OrderProductUpsert {
+	u.Set(orderproduct.FieldQuantity, v)
+	return u
+}
+
+// UpdateQuantity sets the "quantity" field to the value that was provided on create.
+func (u *OrderProductUpsert) UpdateQuantity() *OrderProductUpsert {
+	u.SetExcluded(orderproduct.FieldQuantity)
+	return u
+}
+
+// AddQuantity adds v to the "quantity" field.
+func (u *OrderProductUpsert) AddQuantity(v int) *OrderProductUpsert {
+	u.Add(orderproduct.FieldQuantity, v)
+	return u
+}
+
+// SetPrice sets the "price" field.
+func (u *OrderProductUpsert) SetPrice(v models.Money) *OrderProductUpsert {
+	u.Set(orderproduct.FieldPrice, v)
+	return u
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *OrderProductUpsert) UpdatePrice() *OrderProductUpsert {
+	u.SetExcluded(orderproduct.FieldPrice)
+	return u
+}
+
+// AddPrice adds v to the "price" field.
+func (u *OrderProductUpsert) AddPrice(v models.Money) *OrderProductUpsert {
+	u.Add(orderproduct.FieldPrice, v)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.OrderProduct.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *OrderProductUpsertOne) UpdateNewValues() *OrderProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.OrderProduct.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *OrderProductUpsertOne) Ignore() *OrderProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *OrderProductUpsertOne) DoNothing() *OrderProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the OrderProductCreate.OnConflict
+// documentation for more info.
+func (u *OrderProductUpsertOne) Update(set func(*OrderProductUpsert)) *OrderProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&OrderProductUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetOrderID sets the "order_id" field.
+func (u *OrderProductUpsertOne) SetOrderID(v int) *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetOrderID(v)
+	})
+}
+
+// UpdateOrderID sets the "order_id" field to the value that was provided on create.
+func (u *OrderProductUpsertOne) UpdateOrderID() *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdateOrderID()
+	})
+}
+
+// SetProductID sets the "product_id" field.
+func (u *OrderProductUpsertOne) SetProductID(v int) *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetProductID(v)
+	})
+}
+
+// UpdateProductID sets the "product_id" field to the value that was provided on create.
+func (u *OrderProductUpsertOne) UpdateProductID() *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdateProductID()
+	})
+}
+
+// SetQuantity sets the "quantity" field.
+func (u *OrderProductUpsertOne) SetQuantity(v int) *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetQuantity(v)
+	})
+}
+
+// AddQuantity adds v to the "quantity" field.
+func (u *OrderProductUpsertOne) AddQuantity(v int) *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.AddQuantity(v)
+	})
+}
+
+// UpdateQuantity sets the "quantity" field to the value that was provided on create.
+func (u *OrderProductUpsertOne) UpdateQuantity() *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdateQuantity()
+	})
+}
+
+// SetPrice sets the "price" field.
+func (u *OrderProductUpsertOne) SetPrice(v models.Money) *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetPrice(v)
+	})
+}
+
+// AddPrice adds v to the "price" field.
+func (u *OrderProductUpsertOne) AddPrice(v models.Money) *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.AddPrice(v)
+	})
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *OrderProductUpsertOne) UpdatePrice() *OrderProductUpsertOne {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdatePrice()
+	})
+}
+
+// Exec executes the query.
+func (u *OrderProductUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("generated: missing options for OrderProductCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *OrderProductUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *OrderProductUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *OrderProductUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
 // OrderProductCreateBulk is the builder for creating many OrderProduct entities in bulk.
 type OrderProductCreateBulk struct {
 	config
 	err      error
 	builders []*OrderProductCreate
+	conflict []sql.ConflictOption
 }
 
 // Save creates the OrderProduct entities in the database.
@@ -211,6 +468,7 @@ func (opcb *OrderProductCreateBulk) Save(ctx context.Context) ([]*OrderProduct,
 					_, err = mutators[i+1].Mutate(root, opcb.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = opcb.conflict
 					// Invoke the actual operation on the latest mutation in the chain.
 					if err = sqlgraph.BatchCreate(ctx, opcb.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
@@ -264,3 +522,177 @@ func (opcb *OrderProductCreateBulk) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.OrderProduct.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.OrderProductUpsert) {
+//			SetOrderID(v+v).
+//		}).
+//		Exec(ctx)
+func (opcb *OrderProductCreateBulk) OnConflict(opts ...sql.ConflictOption) *OrderProductUpsertBulk {
+	opcb.conflict = opts
+	return &OrderProductUpsertBulk{
+		create: opcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.OrderProduct.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (opcb *OrderProductCreateBulk) OnConflictColumns(columns ...string) *OrderProductUpsertBulk {
+	opcb.conflict = append(opcb.conflict, sql.ConflictColumns(columns...))
+	return &OrderProductUpsertBulk{
+		create: opcb,
+	}
+}
+
+// OrderProductUpsertBulk is the builder for "upsert"-ing
+// a bulk of OrderProduct nodes.
+type OrderProductUpsertBulk struct {
+	create *OrderProductCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.OrderProduct.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *OrderProductUpsertBulk) UpdateNewValues() *OrderProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.OrderProduct.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *OrderProductUpsertBulk) Ignore() *OrderProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *OrderProductUpsertBulk) DoNothing() *OrderProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the OrderProductCreateBulk.OnConflict
+// documentation for more info.
+func (u *OrderProductUpsertBulk) Update(set func(*OrderProductUpsert)) *OrderProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&OrderProductUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetOrderID sets the "order_id" field.
+func (u *OrderProductUpsertBulk) SetOrderID(v int) *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetOrderID(v)
+	})
+}
+
+// UpdateOrderID sets the "order_id" field to the value that was provided on create.
+func (u *OrderProductUpsertBulk) UpdateOrderID() *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdateOrderID()
+	})
+}
+
+// SetProductID sets the "product_id" field.
+func (u *OrderProductUpsertBulk) SetProductID(v int) *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetProductID(v)
+	})
+}
+
+// UpdateProductID sets the "product_id" field to the value that was provided on create.
+func (u *OrderProductUpsertBulk) UpdateProductID() *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdateProductID()
+	})
+}
+
+// SetQuantity sets the "quantity" field.
+func (u *OrderProductUpsertBulk) SetQuantity(v int) *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetQuantity(v)
+	})
+}
+
+// AddQuantity adds v to the "quantity" field.
+func (u *OrderProductUpsertBulk) AddQuantity(v int) *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.AddQuantity(v)
+	})
+}
+
+// UpdateQuantity sets the "quantity" field to the value that was provided on create.
+func (u *OrderProductUpsertBulk) UpdateQuantity() *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdateQuantity()
+	})
+}
+
+// SetPrice sets the "price" field.
+func (u *OrderProductUpsertBulk) SetPrice(v models.Money) *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.SetPrice(v)
+	})
+}
+
+// AddPrice adds v to the "price" field.
+func (u *OrderProductUpsertBulk) AddPrice(v models.Money) *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.AddPrice(v)
+	})
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *OrderProductUpsertBulk) UpdatePrice() *OrderProductUpsertBulk {
+	return u.Update(func(s *OrderProductUpsert) {
+		s.UpdatePrice()
+	})
+}
+
+// Exec executes the query.
+func (u *OrderProductUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("generated: OnConflict was set for builder %d. Set it on the OrderProductCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("generated: missing options for OrderProductCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *OrderProductUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
@@ -26,6 +27,7 @@ type OrderProductQuery struct {
 	predicates  []predicate.OrderProduct
 	withOrder   *OrderQuery
 	withProduct *ProductQuery
+	modifiers   []func(*sql.Selector)
 	// intermediate query (i.e. traversal path).
 	sql  *sql.Selector
 	path func(context.Context) (*sql.Selector, error)
@@ -301,8 +303,9 @@ func (opq *OrderProductQuery) Clone() *OrderProductQuery {
 		withOrder:   opq.withOrder.Clone(),
 		withProduct: opq.withProduct.Clone(),
 		// clone intermediate query.
-		sql:  opq.sql.Clone(),
-		path: opq.path,
+		sql:       opq.sql.Clone(),
+		path:      opq.path,
+		modifiers: append([]func(*sql.Selector){}, opq.modifiers...),
 	}
 }
 
@@ -420,6 +423,9 @@ func (opq *OrderProductQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([
 		node.Edges.loadedTypes = loadedTypes
 		return node.assignValues(columns, values)
 	}
+	if len(opq.modifiers) > 0 {
+		_spec.Modifiers = opq.modifiers
+	}
 	for i := range hooks {
 		hooks[i](ctx, _spec)
 	}
@@ -505,6 +511,9 @@ func (opq *OrderProductQuery) loadProduct(ctx context.Context, query *ProductQue
 
 func (opq *OrderProductQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := opq.querySpec()
+	if len(opq.modifiers) > 0 {
+		_spec.Modifiers = opq.modifiers
+	}
 	_spec.Node.Columns = opq.ctx.Fields
 	if len(opq.ctx.Fields) > 0 {
 		_spec.Unique = opq.ctx.Unique != nil && *opq.ctx.Unique
@@ -573,6 +582,9 @@ func (opq *OrderProductQuery) sqlQuery(ctx context.Context) *sql.Selector {
 	if opq.ctx.Unique != nil && *opq.ctx.Unique {
 		selector.Distinct()
 	}
+	for _, m := range opq.modifiers {
+		m(selector)
+	}
 	for _, p := range opq.predicates {
 		p(selector)
 	}
@@ -590,6 +602,38 @@ func (opq *OrderProductQuery) sqlQuery(ctx context.Context) *sql.Selector {
 	return selector
 }
 
+// ForUpdate locks the selected rows against concurrent updates, and prevent them from being
+// updated, deleted or "selected ... for update" by other sessions, until the transaction is
+// either committed or rolled-back.
+func (opq *OrderProductQuery) ForUpdate(opts ...sql.LockOption) *OrderProductQuery {
+	if opq.driver.Dialect() == dialect.Postgres {
+		opq.Unique(false)
+	}
+	opq.modifiers = append(opq.modifiers, func(s *sql.Selector) {
+		s.ForUpdate(opts...)
+	})
+	return opq
+}
+
+// ForShare behaves similarly to ForUpdate, except that it acquires a shared mode lock
+// on any rows that are read. Other sessions can read the rows, but cannot modify them
+// until your transaction commits.
+func (opq *OrderProductQuery) ForShare(opts ...sql.LockOption) *OrderProductQuery {
+	if opq.driver.Dialect() == dialect.Postgres {
+		opq.Unique(false)
+	}
+	opq.modifiers = append(opq.modifiers, func(s *sql.Selector) {
+		s.ForShare(opts...)
+	})
+	return opq
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (opq *OrderProductQuery) Modify(modifiers ...func(s *sql.Selector)) *OrderProductSelect {
+	opq.modifiers = append(opq.modifiers, modifiers...)
+	return opq.Select()
+}
+
 // OrderProductGroupBy is the group-by builder for OrderProduct entities.
 type OrderProductGroupBy struct {
 	selector
@@ -679,3 +723,9 @@ func (ops *OrderProductSelect) sqlScan(ctx context.Context, root *OrderProductQu
 	defer rows.Close()
 	return sql.ScanSlice(rows, v)
 }
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ops *OrderProductSelect) Modify(modifiers ...func(s *sql.Selector)) *OrderProductSelect {
+	ops.modifiers = append(ops.modifiers, modifiers...)
+	return ops
+}
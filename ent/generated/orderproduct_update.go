@@ -14,13 +14,15 @@ import (
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/predicate"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // OrderProductUpdate is the builder for updating OrderProduct entities.
 type OrderProductUpdate struct {
 	config
-	hooks    []Hook
-	mutation *OrderProductMutation
+	hooks     []Hook
+	mutation  *OrderProductMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // Where appends a list predicates to the OrderProductUpdate builder.
@@ -79,23 +81,23 @@ func (opu *OrderProductUpdate) AddQuantity(i int) *OrderProductUpdate {
 }
 
 // SetPrice sets the "price" field.
-func (opu *OrderProductUpdate) SetPrice(f float64) *OrderProductUpdate {
+func (opu *OrderProductUpdate) SetPrice(m models.Money) *OrderProductUpdate {
 	opu.mutation.ResetPrice()
-	opu.mutation.SetPrice(f)
+	opu.mutation.SetPrice(m)
 	return opu
 }
 
 // SetNillablePrice sets the "price" field if the given value is not nil.
-func (opu *OrderProductUpdate) SetNillablePrice(f *float64) *OrderProductUpdate {
-	if f != nil {
-		opu.SetPrice(*f)
+func (opu *OrderProductUpdate) SetNillablePrice(m *models.Money) *OrderProductUpdate {
+	if m != nil {
+		opu.SetPrice(*m)
 	}
 	return opu
 }
 
-// AddPrice adds f to the "price" field.
-func (opu *OrderProductUpdate) AddPrice(f float64) *OrderProductUpdate {
-	opu.mutation.AddPrice(f)
+// AddPrice adds m to the "price" field.
+func (opu *OrderProductUpdate) AddPrice(m models.Money) *OrderProductUpdate {
+	opu.mutation.AddPrice(m)
 	return opu
 }
 
@@ -164,6 +166,12 @@ func (opu *OrderProductUpdate) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (opu *OrderProductUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *OrderProductUpdate {
+	opu.modifiers = append(opu.modifiers, modifiers...)
+	return opu
+}
+
 func (opu *OrderProductUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if err := opu.check(); err != nil {
 		return n, err
@@ -246,6 +254,7 @@ func (opu *OrderProductUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(opu.modifiers...)
 	if n, err = sqlgraph.UpdateNodes(ctx, opu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{orderproduct.Label}
@@ -261,9 +270,10 @@ func (opu *OrderProductUpdate) sqlSave(ctx context.Context) (n int, err error) {
 // OrderProductUpdateOne is the builder for updating a single OrderProduct entity.
 type OrderProductUpdateOne struct {
 	config
-	fields   []string
-	hooks    []Hook
-	mutation *OrderProductMutation
+	fields    []string
+	hooks     []Hook
+	mutation  *OrderProductMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // SetOrderID sets the "order_id" field.
@@ -316,23 +326,23 @@ func (opuo *OrderProductUpdateOne) AddQuantity(i int) *OrderProductUpdateOne {
 }
 
 // SetPrice sets the "price" field.
-func (opuo *OrderProductUpdateOne) SetPrice(f float64) *OrderProductUpdateOne {
+func (opuo *OrderProductUpdateOne) SetPrice(m models.Money) *OrderProductUpdateOne {
 	opuo.mutation.ResetPrice()
-	opuo.mutation.SetPrice(f)
+	opuo.mutation.SetPrice(m)
 	return opuo
 }
 
 // SetNillablePrice sets the "price" field if the given value is not nil.
-func (opuo *OrderProductUpdateOne) SetNillablePrice(f *float64) *OrderProductUpdateOne {
-	if f != nil {
-		opuo.SetPrice(*f)
+func (opuo *OrderProductUpdateOne) SetNillablePrice(m *models.Money) *OrderProductUpdateOne {
+	if m != nil {
+		opuo.SetPrice(*m)
 	}
 	return opuo
 }
 
-// AddPrice adds f to the "price" field.
-func (opuo *OrderProductUpdateOne) AddPrice(f float64) *OrderProductUpdateOne {
-	opuo.mutation.AddPrice(f)
+// AddPrice adds m to the "price" field.
+func (opuo *OrderProductUpdateOne) AddPrice(m models.Money) *OrderProductUpdateOne {
+	opuo.mutation.AddPrice(m)
 	return opuo
 }
 
@@ -414,6 +424,12 @@ func (opuo *OrderProductUpdateOne) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (opuo *OrderProductUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *OrderProductUpdateOne {
+	opuo.modifiers = append(opuo.modifiers, modifiers...)
+	return opuo
+}
+
 func (opuo *OrderProductUpdateOne) sqlSave(ctx context.Context) (_node *OrderProduct, err error) {
 	if err := opuo.check(); err != nil {
 		return _node, err
@@ -513,6 +529,7 @@ func (opuo *OrderProductUpdateOne) sqlSave(ctx context.Context) (_node *OrderPro
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(opuo.modifiers...)
 	_node = &OrderProduct{config: opuo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
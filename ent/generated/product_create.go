@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"time"
 
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // ProductCreate is the builder for creating a Product entity.
@@ -19,6 +21,7 @@ type ProductCreate struct {
 	config
 	mutation *ProductMutation
 	hooks    []Hook
+	conflict []sql.ConflictOption
 }
 
 // SetName sets the "name" field.
@@ -42,8 +45,8 @@ func (pc *ProductCreate) SetNillableDescription(s *string) *ProductCreate {
 }
 
 // SetPrice sets the "price" field.
-func (pc *ProductCreate) SetPrice(f float64) *ProductCreate {
-	pc.mutation.SetPrice(f)
+func (pc *ProductCreate) SetPrice(m models.Money) *ProductCreate {
+	pc.mutation.SetPrice(m)
 	return pc
 }
 
@@ -163,13 +166,14 @@ func (pc *ProductCreate) createSpec() (*Product, *sqlgraph.CreateSpec) {
 		_node = &Product{config: pc.config}
 		_spec = sqlgraph.NewCreateSpec(product.Table, sqlgraph.NewFieldSpec(product.FieldID, field.TypeInt))
 	)
+	_spec.OnConflict = pc.conflict
 	if value, ok := pc.mutation.Name(); ok {
 		_spec.SetField(product.FieldName, field.TypeString, value)
 		_node.Name = value
 	}
 	if value, ok := pc.mutation.Description(); ok {
 		_spec.SetField(product.FieldDescription, field.TypeString, value)
-		_node.Description = value
+		_node.Description = &value
 	}
 	if value, ok := pc.mutation.Price(); ok {
 		_spec.SetField(product.FieldPrice, field.TypeFloat64, value)
@@ -202,11 +206,282 @@ func (pc *ProductCreate) createSpec() (*Product, *sqlgraph.CreateSpec) {
 	return _node, _spec
 }
 
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Product.Create().
+//		SetName(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ProductUpsert) {
+//			SetName(v+v).
+//		}).
+//		Exec(ctx)
+func (pc *ProductCreate) OnConflict(opts ...sql.ConflictOption) *ProductUpsertOne {
+	pc.conflict = opts
+	return &ProductUpsertOne{
+		create: pc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (pc *ProductCreate) OnConflictColumns(columns ...string) *ProductUpsertOne {
+	pc.conflict = append(pc.conflict, sql.ConflictColumns(columns...))
+	return &ProductUpsertOne{
+		create: pc,
+	}
+}
+
+type (
+	// ProductUpsertOne is the builder for "upsert"-ing
+	//  one Product node.
+	ProductUpsertOne struct {
+		create *ProductCreate
+	}
+
+	// ProductUpsert is the "OnConflict" setter.
+	ProductUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetName sets the "name" field.
+func (u *ProductUpsert) SetName(v string) *ProductUpsert {
+	u.Set(product.FieldName, v)
+	return u
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateName() *ProductUpsert {
+	u.SetExcluded(product.FieldName)
+	return u
+}
+
+// SetDescription sets the "description" field.
+func (u *ProductUpsert) SetDescription(v string) *ProductUpsert {
+	u.Set(product.FieldDescription, v)
+	return u
+}
+
+// UpdateDescription sets the "description" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateDescription() *ProductUpsert {
+	u.SetExcluded(product.FieldDescription)
+	return u
+}
+
+// ClearDescription clears the value of the "description" field.
+func (u *ProductUpsert) ClearDescription() *ProductUpsert {
+	u.SetNull(product.FieldDescription)
+	return u
+}
+
+// SetPrice sets the "price" field.
+func (u *ProductUpsert) SetPrice(v models.Money) *ProductUpsert {
+	u.Set(product.FieldPrice, v)
+	return u
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *ProductUpsert) UpdatePrice() *ProductUpsert {
+	u.SetExcluded(product.FieldPrice)
+	return u
+}
+
+// AddPrice adds v to the "price" field.
+func (u *ProductUpsert) AddPrice(v models.Money) *ProductUpsert {
+	u.Add(product.FieldPrice, v)
+	return u
+}
+
+// SetStock sets the "stock" field.
+func (u *ProductUpsert) SetStock(v int) *ProductUpsert {
+	u.Set(product.FieldStock, v)
+	return u
+}
+
+// UpdateStock sets the "stock" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateStock() *ProductUpsert {
+	u.SetExcluded(product.FieldStock)
+	return u
+}
+
+// AddStock adds v to the "stock" field.
+func (u *ProductUpsert) AddStock(v int) *ProductUpsert {
+	u.Add(product.FieldStock, v)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ProductUpsertOne) UpdateNewValues() *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.CreatedAt(); exists {
+			s.SetIgnore(product.FieldCreatedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *ProductUpsertOne) Ignore() *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ProductUpsertOne) DoNothing() *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ProductCreate.OnConflict
+// documentation for more info.
+func (u *ProductUpsertOne) Update(set func(*ProductUpsert)) *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ProductUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetName sets the "name" field.
+func (u *ProductUpsertOne) SetName(v string) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetName(v)
+	})
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateName() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateName()
+	})
+}
+
+// SetDescription sets the "description" field.
+func (u *ProductUpsertOne) SetDescription(v string) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetDescription(v)
+	})
+}
+
+// UpdateDescription sets the "description" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateDescription() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateDescription()
+	})
+}
+
+// ClearDescription clears the value of the "description" field.
+func (u *ProductUpsertOne) ClearDescription() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.ClearDescription()
+	})
+}
+
+// SetPrice sets the "price" field.
+func (u *ProductUpsertOne) SetPrice(v models.Money) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetPrice(v)
+	})
+}
+
+// AddPrice adds v to the "price" field.
+func (u *ProductUpsertOne) AddPrice(v models.Money) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.AddPrice(v)
+	})
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdatePrice() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdatePrice()
+	})
+}
+
+// SetStock sets the "stock" field.
+func (u *ProductUpsertOne) SetStock(v int) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetStock(v)
+	})
+}
+
+// AddStock adds v to the "stock" field.
+func (u *ProductUpsertOne) AddStock(v int) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.AddStock(v)
+	})
+}
+
+// UpdateStock sets the "stock" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateStock() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateStock()
+	})
+}
+
+// Exec executes the query.
+func (u *ProductUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("generated: missing options for ProductCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ProductUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *ProductUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *ProductUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
 // ProductCreateBulk is the builder for creating many Product entities in bulk.
 type ProductCreateBulk struct {
 	config
 	err      error
 	builders []*ProductCreate
+	conflict []sql.ConflictOption
 }
 
 // Save creates the Product entities in the database.
@@ -236,6 +511,7 @@ func (pcb *ProductCreateBulk) Save(ctx context.Context) ([]*Product, error) {
 					_, err = mutators[i+1].Mutate(root, pcb.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = pcb.conflict
 					// Invoke the actual operation on the latest mutation in the chain.
 					if err = sqlgraph.BatchCreate(ctx, pcb.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
@@ -289,3 +565,191 @@ func (pcb *ProductCreateBulk) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Product.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ProductUpsert) {
+//			SetName(v+v).
+//		}).
+//		Exec(ctx)
+func (pcb *ProductCreateBulk) OnConflict(opts ...sql.ConflictOption) *ProductUpsertBulk {
+	pcb.conflict = opts
+	return &ProductUpsertBulk{
+		create: pcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (pcb *ProductCreateBulk) OnConflictColumns(columns ...string) *ProductUpsertBulk {
+	pcb.conflict = append(pcb.conflict, sql.ConflictColumns(columns...))
+	return &ProductUpsertBulk{
+		create: pcb,
+	}
+}
+
+// ProductUpsertBulk is the builder for "upsert"-ing
+// a bulk of Product nodes.
+type ProductUpsertBulk struct {
+	create *ProductCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ProductUpsertBulk) UpdateNewValues() *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.CreatedAt(); exists {
+				s.SetIgnore(product.FieldCreatedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *ProductUpsertBulk) Ignore() *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ProductUpsertBulk) DoNothing() *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ProductCreateBulk.OnConflict
+// documentation for more info.
+func (u *ProductUpsertBulk) Update(set func(*ProductUpsert)) *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ProductUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetName sets the "name" field.
+func (u *ProductUpsertBulk) SetName(v string) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetName(v)
+	})
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateName() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateName()
+	})
+}
+
+// SetDescription sets the "description" field.
+func (u *ProductUpsertBulk) SetDescription(v string) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetDescription(v)
+	})
+}
+
+// UpdateDescription sets the "description" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateDescription() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateDescription()
+	})
+}
+
+// ClearDescription clears the value of the "description" field.
+func (u *ProductUpsertBulk) ClearDescription() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.ClearDescription()
+	})
+}
+
+// SetPrice sets the "price" field.
+func (u *ProductUpsertBulk) SetPrice(v models.Money) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetPrice(v)
+	})
+}
+
+// AddPrice adds v to the "price" field.
+func (u *ProductUpsertBulk) AddPrice(v models.Money) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.AddPrice(v)
+	})
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdatePrice() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdatePrice()
+	})
+}
+
+// SetStock sets the "stock" field.
+func (u *ProductUpsertBulk) SetStock(v int) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetStock(v)
+	})
+}
+
+// AddStock adds v to the "stock" field.
+func (u *ProductUpsertBulk) AddStock(v int) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.AddStock(v)
+	})
+}
+
+// UpdateStock sets the "stock" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateStock() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateStock()
+	})
+}
+
+// Exec executes the query.
+func (u *ProductUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("generated: OnConflict was set for builder %d. Set it on the ProductCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("generated: missing options for ProductCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ProductUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
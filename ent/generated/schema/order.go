@@ -4,9 +4,11 @@ import (
 	"time"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // Order holds the schema definition for the Order entity.
@@ -20,7 +22,11 @@ func (Order) Fields() []ent.Field {
 		field.Int("customer_id"),
 		field.Time("date").
 			Default(time.Now),
-		field.Float("total"),
+		field.Float("total").
+			GoType(models.Money(0)).
+			SchemaType(map[string]string{
+				dialect.Postgres: "numeric(10,2)",
+			}),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
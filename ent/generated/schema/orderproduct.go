@@ -2,9 +2,11 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // OrderProduct holds the schema definition for the OrderProduct entity.
@@ -18,7 +20,11 @@ func (OrderProduct) Fields() []ent.Field {
 		field.Int("order_id"),
 		field.Int("product_id"),
 		field.Int("quantity"),
-		field.Float("price"),
+		field.Float("price").
+			GoType(models.Money(0)).
+			SchemaType(map[string]string{
+				dialect.Postgres: "numeric(10,2)",
+			}),
 	}
 }
 
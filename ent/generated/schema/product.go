@@ -4,9 +4,11 @@ import (
 	"time"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
 )
 
 // Product holds the schema definition for the Product entity.
@@ -18,8 +20,14 @@ type Product struct {
 func (Product) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("name"),
-		field.String("description").Optional(),
-		field.Float("price"),
+		field.String("description").
+			Optional().
+			Nillable(),
+		field.Float("price").
+			GoType(models.Money(0)).
+			SchemaType(map[string]string{
+				dialect.Postgres: "numeric(10,2)",
+			}),
 		field.Int("stock"),
 		field.Time("created_at").
 			Default(time.Now).
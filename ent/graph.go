@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+
+	"entgo.io/ent/dialect"
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/customer"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// countingDriver counts the statements Ent sends outside transactions, including the ones eager loading
+// issues for every edge.
+type countingDriver struct {
+	queries atomic.Int64
+	dialect.Driver
+}
+
+func (d *countingDriver) Exec(ctx context.Context, query string, args, v any) error {
+	d.queries.Add(1)
+	return d.Driver.Exec(ctx, query, args, v)
+}
+
+func (d *countingDriver) Query(ctx context.Context, query string, args, v any) error {
+	d.queries.Add(1)
+	return d.Driver.Query(ctx, query, args, v)
+}
+
+// customerGraph holds the customers of an iteration and converts the loaded entities for the check.
+// Its client must run on Driver.
+type customerGraph struct {
+	CustomerIDs utils.Generator
+	Driver      *countingDriver
+	ids         []int
+	customers   []*ent.Customer
+	Ent
+}
+
+func (g *customerGraph) BeforeEach(int) error {
+	var err error
+	g.ids, err = reference.NextGraphCustomers(g.CustomerIDs)
+
+	return err
+}
+
+func (g *customerGraph) RoundTrips() int64 {
+	return g.Driver.queries.Load()
+}
+
+func (g *customerGraph) Check(int) error {
+	customers := make([]models.Customer, len(g.customers))
+	for i, c := range g.customers {
+		customers[i] = models.Customer{
+			ID:        c.ID,
+			Name:      c.Name,
+			Email:     c.Email,
+			CreatedAt: c.CreatedAt,
+		}
+
+		for _, o := range c.Edges.Orders {
+			ord := models.Order{
+				ID:         o.ID,
+				CustomerID: o.CustomerID,
+				Date:       o.Date,
+				Total:      o.Total,
+				CreatedAt:  o.CreatedAt,
+			}
+
+			for _, line := range o.Edges.Products {
+				item := models.OrderProduct{
+					ID:        line.ID,
+					OrderID:   line.OrderID,
+					ProductID: line.ProductID,
+					Quantity:  line.Quantity,
+					Price:     line.Price,
+				}
+				if p := line.Edges.Product; p != nil {
+					item.Product = models.Product{
+						ID:          p.ID,
+						Name:        p.Name,
+						Description: p.Description,
+						Price:       p.Price,
+						Stock:       p.Stock,
+						CreatedAt:   p.CreatedAt,
+					}
+				}
+				ord.Products = append(ord.Products, item)
+			}
+
+			customers[i].Orders = append(customers[i].Orders, ord)
+		}
+	}
+
+	return reference.CheckCustomerGraph(context.Background(), g.db, g.ids, customers)
+}
+
+type LoadCustomerGraphNPlusOne struct {
+	customerGraph
+}
+
+func (op *LoadCustomerGraphNPlusOne) Name() string {
+	return "Load Customer Graph (N+1)"
+}
+
+// Execute walks the edges one entity at a time and stores them in the edges eager loading would fill.
+func (op *LoadCustomerGraphNPlusOne) Execute(int) error {
+	ctx := context.Background()
+
+	op.customers = make([]*ent.Customer, len(op.ids))
+	for i, id := range op.ids {
+		c, err := op.client.Customer.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		op.customers[i] = c
+
+		c.Edges.Orders, err = op.client.Customer.
+			QueryOrders(c).
+			Order(ent.Asc(order.FieldID)).
+			All(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range c.Edges.Orders {
+			o.Edges.Products, err = op.client.Order.
+				QueryProducts(o).
+				Order(ent.Asc(orderproduct.FieldID)).
+				All(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, line := range o.Edges.Products {
+				line.Edges.Product, err = op.client.OrderProduct.
+					QueryProduct(line).
+					Only(ctx)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+type LoadCustomerGraphEager struct {
+	customerGraph
+}
+
+func (op *LoadCustomerGraphEager) Name() string {
+	return "Load Customer Graph (Eager)"
+}
+
+func (op *LoadCustomerGraphEager) Execute(int) (err error) {
+	op.customers, err = op.client.Customer.
+		Query().
+		Where(customer.IDIn(op.ids...)).
+		WithOrders(func(q *ent.OrderQuery) {
+			q.WithProducts(func(q *ent.OrderProductQuery) {
+				q.WithProduct()
+			})
+		}).
+		All(context.Background())
+
+	return err
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// reservedProducts is the number of distinct products every checkout reserves.
+const reservedProducts = 3
+
+type checkout struct {
+	customerID int
+	productIDs []int
+}
+
+// ReserveInventory runs a checkout in each of Workers concurrent transactions: it locks the products,
+// verifies and decrements their stock, and places the order with totals computed from the locked prices.
+// A checkout of a product out of stock is rolled back and counted as rejected.
+// AfterEach restores the stock of the products and deletes the orders outside the timed section,
+// so every iteration reserves from the initial stock.
+type ReserveInventory struct {
+	CustomerIDs utils.Generator
+	ProductIDs  utils.Generator
+	Workers     int
+	checkouts   []checkout
+	stock       map[int]int
+	reserved    []int
+	lastOrderID int
+	utils.Contention
+	Ent
+}
+
+func (op *ReserveInventory) Name() string {
+	return "Reserve Inventory (Checkout)"
+}
+
+func (op *ReserveInventory) Setup() (err error) {
+	ctx := context.Background()
+
+	op.lastOrderID, err = lastID(
+		op.client.Order.
+			Query().
+			Order(ent.Desc(order.FieldID)).
+			FirstID(ctx),
+	)
+	if err != nil {
+		return err
+	}
+
+	products, err := op.client.Product.
+		Query().
+		Select(product.FieldStock).
+		All(ctx)
+	if err != nil {
+		return err
+	}
+
+	op.stock = make(map[int]int, len(products))
+	for _, p := range products {
+		op.stock[p.ID] = p.Stock
+	}
+
+	return nil
+}
+
+func (op *ReserveInventory) BeforeEach(int) error {
+	op.checkouts = make([]checkout, op.Workers)
+	op.reserved = op.reserved[:0]
+	for w := range op.checkouts {
+		productIDs, err := utils.NextDistinct(op.ProductIDs, min(reservedProducts, op.ProductIDs.Len()))
+		if err != nil {
+			return err
+		}
+
+		op.checkouts[w] = checkout{
+			customerID: op.CustomerIDs.Next(),
+			productIDs: productIDs,
+		}
+		op.reserved = append(op.reserved, productIDs...)
+	}
+
+	return nil
+}
+
+func (op *ReserveInventory) Execute(int) error {
+	return op.reserve(true)
+}
+
+// reserve runs the checkouts of the iteration concurrently. Ordered checkouts lock their products in id order,
+// so concurrent checkouts of the same products wait for each other instead of deadlocking.
+func (op *ReserveInventory) reserve(ordered bool) error {
+	return utils.Parallel(op.Workers, func(w int) error {
+		return op.Reserve(func() (bool, error) {
+			return op.checkout(op.checkouts[w], ordered)
+		})
+	})
+}
+
+func (op *ReserveInventory) checkout(c checkout, ordered bool) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := op.client.Tx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var products []*ent.Product
+	if ordered {
+		err = op.Lock(func() (err error) {
+			products, err = tx.Product.
+				Query().
+				Where(product.IDIn(c.productIDs...)).
+				Order(ent.Asc(product.FieldID)).
+				ForUpdate().
+				All(ctx)
+			return err
+		})
+		if err != nil {
+			return false, rollback(tx, err)
+		}
+	} else {
+		products = make([]*ent.Product, len(c.productIDs))
+		for i, id := range c.productIDs {
+			err = op.Lock(func() (err error) {
+				products[i], err = tx.Product.
+					Query().
+					Where(product.ID(id)).
+					ForUpdate().
+					Only(ctx)
+				return err
+			})
+			if err != nil {
+				return false, rollback(tx, err)
+			}
+		}
+	}
+
+	var total models.Money
+	for _, p := range products {
+		if p.Stock < orderQuantity {
+			return false, tx.Rollback()
+		}
+		total += p.Price.Mul(orderQuantity)
+	}
+
+	_, err = tx.Product.
+		Update().
+		Where(product.IDIn(c.productIDs...)).
+		AddStock(-orderQuantity).
+		Save(ctx)
+	if err != nil {
+		return false, rollback(tx, err)
+	}
+
+	o, err := tx.Order.
+		Create().
+		SetCustomerID(c.customerID).
+		SetDate(time.Now()).
+		SetTotal(total).
+		Save(ctx)
+	if err != nil {
+		return false, rollback(tx, err)
+	}
+
+	lines := make([]*ent.OrderProductCreate, len(products))
+	for i, p := range products {
+		lines[i] = tx.OrderProduct.
+			Create().
+			SetOrderID(o.ID).
+			SetProductID(p.ID).
+			SetQuantity(orderQuantity).
+			SetPrice(p.Price)
+	}
+
+	if err := tx.OrderProduct.CreateBulk(lines...).Exec(ctx); err != nil {
+		return false, rollback(tx, err)
+	}
+
+	return true, tx.Commit()
+}
+
+func (op *ReserveInventory) Check(int) error {
+	return reference.CheckInventory(context.Background(), op.db, op.stock, op.lastOrderID)
+}
+
+func (op *ReserveInventory) AfterEach(int) error {
+	ctx := context.Background()
+
+	stocks := make([]int, len(op.reserved))
+	for i, id := range op.reserved {
+		stocks[i] = op.stock[id]
+	}
+
+	if _, err := op.client.ExecContext(
+		ctx,
+		`UPDATE products p SET stock = s.stock
+        FROM unnest($1::int[], $2::int[]) AS s(id, stock)
+        WHERE p.id = s.id`,
+		pq.Array(op.reserved),
+		pq.Array(stocks),
+	); err != nil {
+		return err
+	}
+
+	_, err := op.client.Order.
+		Delete().
+		Where(order.IDGT(op.lastOrderID)).
+		Exec(ctx)
+
+	return err
+}
+
+// ReserveInventoryUnordered locks the products of a checkout one by one in the order they were drawn,
+// so concurrent checkouts sharing products can deadlock and are retried.
+type ReserveInventoryUnordered struct {
+	ReserveInventory
+}
+
+func (op *ReserveInventoryUnordered) Name() string {
+	return op.ReserveInventory.Name() + " (Unordered Locks)"
+}
+
+func (op *ReserveInventoryUnordered) Execute(int) error {
+	return op.reserve(false)
+}
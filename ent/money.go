@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/order"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// The float64 variants read the same rows, but scan prices and totals into float64.
+// Ent generates models.Money setters for every price and total field, so the writes bind their float64
+// through the sql/modifier hook of the update builder, and through Ent's dialect/sql insert builder
+// on the transaction where the generated create builders have no such hook.
+
+const productPriceFloat64 = 99.99
+
+type GetProductByIDFloat64 struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDFloat64) Name() string {
+	return op.GetProductByID.Name() + " (float64)"
+}
+
+func (op *GetProductByIDFloat64) Execute(int) error {
+	var products []struct {
+		ID          int       `sql:"id"`
+		Name        string    `sql:"name"`
+		Description *string   `sql:"description"`
+		Price       float64   `sql:"price"`
+		Stock       int       `sql:"stock"`
+		CreatedAt   time.Time `sql:"created_at"`
+	}
+
+	return op.client.Product.
+		Query().
+		Where(product.ID(op.IDs.Next())).
+		Select(
+			product.FieldID,
+			product.FieldName,
+			product.FieldDescription,
+			product.FieldPrice,
+			product.FieldStock,
+			product.FieldCreatedAt,
+		).
+		Scan(context.Background(), &products)
+}
+
+type UpdateProductByIDFloat64 struct {
+	IDs   utils.Generator
+	Price float64
+	Ent
+}
+
+func (op *UpdateProductByIDFloat64) Name() string {
+	return "Update Product Price by ID (float64)"
+}
+
+func (op *UpdateProductByIDFloat64) Execute(int) error {
+	return op.client.Product.
+		UpdateOneID(op.IDs.Next()).
+		Modify(func(u *entsql.UpdateBuilder) {
+			u.Set(product.FieldPrice, op.Price)
+		}).
+		Exec(context.Background())
+}
+
+type CreateOrderWithProductsByCustomerIDFloat64 struct {
+	CreateOrderWithProductsByCustomerID
+}
+
+func (op *CreateOrderWithProductsByCustomerIDFloat64) Name() string {
+	return op.CreateOrderWithProductsByCustomerID.Name() + " (float64)"
+}
+
+func (op *CreateOrderWithProductsByCustomerIDFloat64) Execute(int) error {
+	tx, err := op.client.Tx(context.Background())
+	if err != nil {
+		return err
+	}
+
+	query, args := entsql.Dialect(dialect.Postgres).
+		Insert(order.Table).
+		Columns(order.FieldCustomerID, order.FieldDate, order.FieldTotal).
+		Values(op.CustomerIDs.Next(), time.Now(), productPriceFloat64*orderQuantity).
+		Returning(order.FieldID).
+		Query()
+
+	rows, err := tx.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return rollback(tx, err)
+	}
+
+	var orderID int
+	if rows.Next() {
+		err = rows.Scan(&orderID)
+	}
+	if err := errors.Join(err, rows.Err(), rows.Close()); err != nil {
+		return rollback(tx, err)
+	}
+
+	query, args = entsql.Dialect(dialect.Postgres).
+		Insert(orderproduct.Table).
+		Columns(
+			orderproduct.FieldOrderID,
+			orderproduct.FieldProductID,
+			orderproduct.FieldQuantity,
+			orderproduct.FieldPrice,
+		).
+		Values(orderID, op.ProductIDs.Next(), orderQuantity, productPriceFloat64).
+		Query()
+
+	if _, err := tx.ExecContext(context.Background(), query, args...); err != nil {
+		return rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
+
+type GetCustomerStatsByIDFloat64 struct {
+	result struct {
+		TotalOrders int64
+		TotalSpent  float64
+	}
+	GetCustomerStatsByID
+}
+
+func (op *GetCustomerStatsByIDFloat64) Name() string {
+	return op.GetCustomerStatsByID.Name() + " (float64)"
+}
+
+func (op *GetCustomerStatsByIDFloat64) Execute(int) error {
+	op.customerID = op.CustomerIDs.Next()
+
+	var results []struct {
+		TotalOrders int64   `sql:"total_orders"`
+		TotalSpent  float64 `sql:"total_spent"`
+	}
+	if err := op.client.Order.
+		Query().
+		Where(order.CustomerID(op.customerID)).
+		Aggregate(
+			ent.As(ent.Count(), "total_orders"),
+			sumOrZero(order.FieldTotal, "total_spent"),
+		).
+		Scan(context.Background(), &results); err != nil {
+		return err
+	}
+
+	op.result.TotalOrders, op.result.TotalSpent = results[0].TotalOrders, results[0].TotalSpent
+
+	return nil
+}
+
+func (op *GetCustomerStatsByIDFloat64) Check(int) error {
+	return reference.CheckCustomerStats(context.Background(), op.db, op.customerID, reference.CustomerStats{
+		TotalOrders: op.result.TotalOrders,
+		TotalSpent:  models.MoneyFromFloat(op.result.TotalSpent),
+	})
+}
+
+type productSalesFloat64 struct {
+	ProductID   int     `sql:"product_id"`
+	ProductName string  `sql:"product_name"`
+	TotalSales  int64   `sql:"total_sales"`
+	Revenue     float64 `sql:"revenue"`
+}
+
+type GetProductSalesByLimitFloat64 struct {
+	results []productSalesFloat64
+	GetProductSalesByLimit
+}
+
+func (op *GetProductSalesByLimitFloat64) Name() string {
+	return op.GetProductSalesByLimit.Name() + " (float64)"
+}
+
+func (op *GetProductSalesByLimitFloat64) Execute(int) error {
+	op.results = op.results[:0]
+	return op.client.OrderProduct.
+		Query().
+		Modify(func(s *entsql.Selector) {
+			p := entsql.Table(product.Table)
+			s.Join(p).
+				On(s.C(orderproduct.ProductColumn), p.C(product.FieldID)).
+				Select(
+					entsql.As(s.C(orderproduct.ProductColumn), "product_id"),
+					entsql.As(p.C(product.FieldName), "product_name"),
+					entsql.As(entsql.Sum(s.C(orderproduct.FieldQuantity)), "total_sales"),
+					entsql.As(entsql.Sum(s.C(orderproduct.FieldPrice)+" * "+s.C(orderproduct.FieldQuantity)), "revenue"),
+				).
+				GroupBy(s.C(orderproduct.ProductColumn), p.C(product.FieldName)).
+				OrderBy(entsql.Desc("revenue")).
+				Limit(op.Limit)
+		}).
+		Scan(context.Background(), &op.results)
+}
+
+func (op *GetProductSalesByLimitFloat64) Check(int) error {
+	results := make([]reference.ProductSales, len(op.results))
+	for i, r := range op.results {
+		results[i] = reference.ProductSales{
+			ProductID:   r.ProductID,
+			ProductName: r.ProductName,
+			TotalSales:  r.TotalSales,
+			Revenue:     models.MoneyFromFloat(r.Revenue),
+		}
+	}
+
+	return reference.CheckProductSales(context.Background(), op.db, op.Limit, results)
+}
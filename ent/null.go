@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+)
+
+// The description field is Nillable, so the base Get Product by ID reads it into a *string.
+// The generated entity has no other shape for it, so both variants Scan into their own structs;
+// the COALESCE one rewrites the selected columns through the sql/modifier feature.
+
+type GetProductByIDNullString struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDNullString) Name() string {
+	return op.GetProductByID.Name() + " (sql.NullString)"
+}
+
+func (op *GetProductByIDNullString) Execute(int) error {
+	var products []struct {
+		ID          int            `sql:"id"`
+		Name        string         `sql:"name"`
+		Description sql.NullString `sql:"description"`
+		Price       models.Money   `sql:"price"`
+		Stock       int            `sql:"stock"`
+		CreatedAt   time.Time      `sql:"created_at"`
+	}
+
+	return op.client.Product.
+		Query().
+		Where(product.ID(op.IDs.Next())).
+		Select(
+			product.FieldID,
+			product.FieldName,
+			product.FieldDescription,
+			product.FieldPrice,
+			product.FieldStock,
+			product.FieldCreatedAt,
+		).
+		Scan(context.Background(), &products)
+}
+
+type GetProductByIDCoalesce struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDCoalesce) Name() string {
+	return op.GetProductByID.Name() + " (COALESCE)"
+}
+
+func (op *GetProductByIDCoalesce) Execute(int) error {
+	var products []struct {
+		ID          int          `sql:"id"`
+		Name        string       `sql:"name"`
+		Description string       `sql:"description"`
+		Price       models.Money `sql:"price"`
+		Stock       int          `sql:"stock"`
+		CreatedAt   time.Time    `sql:"created_at"`
+	}
+
+	return op.client.Product.
+		Query().
+		Where(product.ID(op.IDs.Next())).
+		Modify(func(s *entsql.Selector) {
+			s.Select(
+				s.C(product.FieldID),
+				s.C(product.FieldName),
+				entsql.As("COALESCE("+s.C(product.FieldDescription)+", '')", product.FieldDescription),
+				s.C(product.FieldPrice),
+				s.C(product.FieldStock),
+				s.C(product.FieldCreatedAt),
+			)
+		}).
+		Scan(context.Background(), &products)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// productWalk keeps the entities every page's All loaded and their id sum for the check.
+type productWalk struct {
+	rows  int
+	idSum int64
+	utils.PageTimes
+	Ent
+}
+
+func (w *productWalk) Check(int) error {
+	return reference.CheckProductWalk(context.Background(), w.db, w.rows, w.idSum)
+}
+
+func (w *productWalk) count(products []*ent.Product) {
+	for _, p := range products {
+		w.rows++
+		w.idSum += int64(p.ID)
+	}
+}
+
+type PaginateProductsOffset struct {
+	productWalk
+}
+
+func (op *PaginateProductsOffset) Name() string {
+	return "Paginate Products (Offset)"
+}
+
+func (op *PaginateProductsOffset) Execute(int) error {
+	op.rows, op.idSum = 0, 0
+	for page := 0; ; page++ {
+		start := time.Now()
+
+		products, err := op.client.Product.
+			Query().
+			Order(ent.Asc(product.FieldID)).
+			Limit(utils.PageSize).
+			Offset(page * utils.PageSize).
+			All(context.Background())
+		if err != nil {
+			return err
+		}
+		op.Add(page, time.Since(start))
+		op.count(products)
+
+		if len(products) < utils.PageSize {
+			return nil
+		}
+	}
+}
+
+type PaginateProductsKeyset struct {
+	productWalk
+}
+
+func (op *PaginateProductsKeyset) Name() string {
+	return "Paginate Products (Keyset)"
+}
+
+func (op *PaginateProductsKeyset) Execute(int) error {
+	op.rows, op.idSum = 0, 0
+	for page, last := 0, 0; ; page++ {
+		start := time.Now()
+
+		products, err := op.client.Product.
+			Query().
+			Where(product.IDGT(last)).
+			Order(ent.Asc(product.FieldID)).
+			Limit(utils.PageSize).
+			All(context.Background())
+		if err != nil {
+			return err
+		}
+		op.Add(page, time.Since(start))
+		op.count(products)
+
+		if len(products) < utils.PageSize {
+			return nil
+		}
+		last = products[len(products)-1].ID
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+)
+
+// The raw variants bypass Ent and run hand-written SQL on the database/sql handle underneath it,
+// so they measure database/sql rather than Ent.
+
+type GetCustomerStatsByIDRaw struct {
+	GetCustomerStatsByID
+}
+
+func (op *GetCustomerStatsByIDRaw) Name() string {
+	return op.GetCustomerStatsByID.Name() + " (Raw SQL)"
+}
+
+func (op *GetCustomerStatsByIDRaw) Execute(int) error {
+	op.customerID = op.CustomerIDs.Next()
+	rows, err := op.db.
+		QueryContext(
+			context.Background(),
+			"SELECT COUNT(*) AS total_orders, COALESCE(SUM(total), 0) AS total_spent FROM orders WHERE customer_id = $1",
+			op.customerID,
+		)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&op.result.TotalOrders, &op.result.TotalSpent); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+type GetProductSalesByLimitRaw struct {
+	GetProductSalesByLimit
+}
+
+func (op *GetProductSalesByLimitRaw) Name() string {
+	return op.GetProductSalesByLimit.Name() + " (Raw SQL)"
+}
+
+func (op *GetProductSalesByLimitRaw) Execute(int) error {
+	rows, err := op.db.
+		QueryContext(
+			context.Background(),
+			`SELECT 
+            p.id, 
+            p.name, 
+            SUM(op.quantity) AS total_sales, 
+            SUM(op.price * op.quantity) AS revenue
+        FROM order_products op
+        JOIN products p ON p.id = op.product_id
+        GROUP BY p.id, p.name
+        ORDER BY revenue DESC
+        LIMIT $1`,
+			op.Limit,
+		)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.results = op.results[:0]
+	for rows.Next() {
+		var r reference.ProductSales
+		if err := rows.Scan(
+			&r.ProductID,
+			&r.ProductName,
+			&r.TotalSales,
+			&r.Revenue,
+		); err != nil {
+			return err
+		}
+		op.results = append(op.results, r)
+	}
+
+	return rows.Err()
+}
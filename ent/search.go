@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/predicate"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// searchLimit is the number of products on the page a search returns.
+const searchLimit = 50
+
+// SearchProducts runs a product search with a random combination of filters in every iteration.
+// It reports how long building the query and running it took on their own.
+type SearchProducts struct {
+	Filters  *utils.FilterGenerator
+	filter   utils.ProductFilter
+	products []*ent.Product
+	utils.PhaseTimes
+	Ent
+}
+
+func (op *SearchProducts) Name() string {
+	return "Search Products (Dynamic Filters)"
+}
+
+func (op *SearchProducts) BeforeEach(int) error {
+	op.filter = op.Filters.Next()
+
+	return nil
+}
+
+// build collects the predicates of the applied filters. Ent renders the SQL itself when the query runs.
+func (op *SearchProducts) build() *ent.ProductQuery {
+	f := op.filter
+
+	var predicates []predicate.Product
+	if f.MinPrice != nil {
+		predicates = append(predicates, product.PriceGTE(*f.MinPrice))
+	}
+	if f.MaxPrice != nil {
+		predicates = append(predicates, product.PriceLTE(*f.MaxPrice))
+	}
+	if f.InStock {
+		predicates = append(predicates, product.StockGT(0))
+	}
+	if f.Name != "" {
+		predicates = append(predicates, product.NameContainsFold(f.Name))
+	}
+	if f.CreatedFrom != nil {
+		predicates = append(predicates, product.CreatedAtGTE(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		predicates = append(predicates, product.CreatedAtLT(*f.CreatedTo))
+	}
+
+	query := op.client.Product.Query()
+	if len(predicates) > 0 {
+		query.Where(product.And(predicates...))
+	}
+
+	return query.
+		Order(ent.Asc(product.FieldID)).
+		Limit(searchLimit)
+}
+
+func (op *SearchProducts) Execute(int) (err error) {
+	start := time.Now()
+	query := op.build()
+	op.Add("build", time.Since(start))
+
+	start = time.Now()
+	op.products, err = query.All(context.Background())
+	op.Add("execute", time.Since(start))
+
+	return err
+}
+
+func (op *SearchProducts) Check(int) error {
+	ids := make([]int, len(op.products))
+	for i, p := range op.products {
+		ids[i] = p.ID
+	}
+
+	return reference.CheckProductSearch(context.Background(), op.db, op.filter, searchLimit, ids)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/orderproduct"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+)
+
+// orderProductStream is embedded by the Ent streaming operations, which differ only in how they fetch the lines.
+type orderProductStream struct {
+	reference.OrderProductStream
+	Ent
+}
+
+// Setup reads the ids of the lines themselves, as Ent cannot aggregate over a limited subquery.
+func (s *orderProductStream) Setup() error {
+	ids, err := s.client.OrderProduct.
+		Query().
+		Order(ent.Asc(orderproduct.FieldID)).
+		Limit(s.Limit).
+		IDs(context.Background())
+	if err != nil {
+		return err
+	}
+
+	s.N, s.LastID = len(ids), 0
+	if len(ids) > 0 {
+		s.LastID = ids[len(ids)-1]
+	}
+
+	return nil
+}
+
+func (s *orderProductStream) add(lines []*ent.OrderProduct) {
+	for _, line := range lines {
+		s.Add(line.Price, line.Quantity)
+	}
+}
+
+func (s *orderProductStream) Check(int) error {
+	return s.OrderProductStream.Check(context.Background(), s.db)
+}
+
+type StreamOrderProductsAll struct {
+	orderProductStream
+}
+
+func (op *StreamOrderProductsAll) Name() string {
+	return "Stream Order Products (All)"
+}
+
+// Execute materializes every line with All before reading them.
+func (op *StreamOrderProductsAll) Execute(int) error {
+	lines, err := op.client.OrderProduct.
+		Query().
+		Where(orderproduct.IDLTE(op.LastID)).
+		Order(ent.Asc(orderproduct.FieldID)).
+		All(context.Background())
+	if err != nil {
+		return err
+	}
+
+	op.Reset()
+	op.add(lines)
+
+	return nil
+}
+
+type StreamOrderProductsKeyset struct {
+	BatchSize int
+	orderProductStream
+}
+
+func (op *StreamOrderProductsKeyset) Name() string {
+	return "Stream Order Products (Keyset Chunks)"
+}
+
+// Execute reads BatchSize lines per query, each chunk after the last id of the previous one.
+func (op *StreamOrderProductsKeyset) Execute(int) error {
+	op.Reset()
+	for last := 0; ; {
+		lines, err := op.client.OrderProduct.
+			Query().
+			Where(
+				orderproduct.IDGT(last),
+				orderproduct.IDLTE(op.LastID),
+			).
+			Order(ent.Asc(orderproduct.FieldID)).
+			Limit(op.BatchSize).
+			All(context.Background())
+		if err != nil {
+			return err
+		}
+		op.add(lines)
+
+		if len(lines) < op.BatchSize {
+			return nil
+		}
+		last = lines[len(lines)-1].ID
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/lib/pq"
+	ent "github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/product"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// reprice raises the price by 10% and adds Restock to the stock of the products the update selects,
+// keeping the number of rows Save updated. Ent has no expression setter for regular fields,
+// so the price expression is set with a modifier.
+func (u *productUpdate) reprice(update *ent.ProductUpdate) error {
+	var err error
+	u.affected, err = update.
+		AddStock(reference.Restock).
+		Modify(func(b *entsql.UpdateBuilder) {
+			b.Set(product.FieldPrice, entsql.Expr(product.FieldPrice+" * 1.1"))
+		}).
+		Save(context.Background())
+
+	return err
+}
+
+// productUpdate is embedded by the Ent update operations, which reprice the products their update selects.
+type productUpdate struct {
+	products map[int]models.Product
+	ids      []int
+	affected int
+	Ent
+}
+
+func (u *productUpdate) Setup() error {
+	products, err := u.client.Product.
+		Query().
+		Select(product.FieldPrice, product.FieldStock).
+		All(context.Background())
+	if err != nil {
+		return err
+	}
+
+	u.products = make(map[int]models.Product, len(products))
+	for _, p := range products {
+		u.products[p.ID] = models.Product{ID: p.ID, Price: p.Price, Stock: p.Stock}
+	}
+
+	return nil
+}
+
+// Rows returns the number of rows the last execution's Save reported as updated.
+func (u *productUpdate) Rows() int {
+	return u.affected
+}
+
+func (u *productUpdate) Check(int) error {
+	return reference.CheckProductsRepriced(context.Background(), u.db, u.products, u.ids)
+}
+
+func (u *productUpdate) AfterEach(int) error {
+	prices := make([]string, len(u.ids))
+	stocks := make([]int, len(u.ids))
+	for i, id := range u.ids {
+		prices[i] = u.products[id].Price.String()
+		stocks[i] = u.products[id].Stock
+	}
+
+	_, err := u.client.ExecContext(
+		context.Background(),
+		`UPDATE products p SET price = s.price, stock = s.stock
+        FROM unnest($1::int[], $2::numeric[], $3::int[]) AS s(id, price, stock)
+        WHERE p.id = s.id`,
+		pq.Array(u.ids),
+		pq.Array(prices),
+		pq.Array(stocks),
+	)
+
+	return err
+}
+
+type UpdateProductByExpression struct {
+	IDs utils.Generator
+	productUpdate
+}
+
+func (op *UpdateProductByExpression) Name() string {
+	return "Update Product by Expression"
+}
+
+func (op *UpdateProductByExpression) BeforeEach(int) error {
+	op.ids = []int{op.IDs.Next()}
+
+	return nil
+}
+
+func (op *UpdateProductByExpression) Execute(int) error {
+	return op.reprice(op.client.Product.
+		Update().
+		Where(product.ID(op.ids[0])),
+	)
+}
+
+type UpdateProductsByIDs struct {
+	IDs utils.Generator
+	productUpdate
+}
+
+func (op *UpdateProductsByIDs) Name() string {
+	return "Update Products by Expression (IN)"
+}
+
+func (op *UpdateProductsByIDs) BeforeEach(int) error {
+	var err error
+	op.ids, err = reference.NextUpdateBatch(op.IDs)
+
+	return err
+}
+
+func (op *UpdateProductsByIDs) Execute(int) error {
+	return op.reprice(op.client.Product.
+		Update().
+		Where(product.IDIn(op.ids...)),
+	)
+}
+
+// UpdateProductsByPriceBand updates every product cheaper than CheapPrice, the same products in every
+// iteration since they are restored in between.
+type UpdateProductsByPriceBand struct {
+	productUpdate
+}
+
+func (op *UpdateProductsByPriceBand) Name() string {
+	return "Update Products by Expression (Price Band)"
+}
+
+func (op *UpdateProductsByPriceBand) Setup() error {
+	if err := op.productUpdate.Setup(); err != nil {
+		return err
+	}
+
+	for id, p := range op.products {
+		if p.Price < reference.CheapPrice {
+			op.ids = append(op.ids, id)
+		}
+	}
+
+	return nil
+}
+
+func (op *UpdateProductsByPriceBand) Execute(int) error {
+	return op.reprice(op.client.Product.
+		Update().
+		Where(product.PriceLT(reference.CheapPrice)),
+	)
+}
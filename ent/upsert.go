@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/customer"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// UpsertCustomerByEmail creates a customer with OnConflictColumns(email) and UpdateName from the
+// sql/upsert feature, which sets only the name from the excluded row instead of every field as
+// UpdateNewValues would. ConflictRatio is the share of iterations that reuse an existing email.
+// Teardown restores the names and deletes the inserted customers.
+type UpsertCustomerByEmail struct {
+	CustomerIDs   utils.Generator
+	ConflictRatio float64
+	customers     map[int]models.Customer
+	renamed       map[int]bool
+	email         string
+	name          string
+	lastID        int
+	Ent
+}
+
+func (op *UpsertCustomerByEmail) Name() string {
+	return "Upsert Customer by Email"
+}
+
+func (op *UpsertCustomerByEmail) Setup() error {
+	customers, err := op.client.Customer.
+		Query().
+		Select(customer.FieldName, customer.FieldEmail).
+		All(context.Background())
+	if err != nil {
+		return err
+	}
+
+	op.customers = make(map[int]models.Customer, len(customers))
+	op.renamed = make(map[int]bool)
+	for _, c := range customers {
+		op.customers[c.ID] = models.Customer{ID: c.ID, Name: c.Name, Email: c.Email}
+		op.lastID = max(op.lastID, c.ID)
+	}
+
+	return nil
+}
+
+func (op *UpsertCustomerByEmail) BeforeEach(iteration int) error {
+	op.name = fmt.Sprintf("Customer_%d", iteration)
+	op.email = fmt.Sprintf("customer_%d@upsert.test", iteration)
+
+	if float64(iteration%100) < op.ConflictRatio*100 {
+		id := op.CustomerIDs.Next()
+		op.email = op.customers[id].Email
+		op.renamed[id] = true
+	}
+
+	return nil
+}
+
+func (op *UpsertCustomerByEmail) Execute(int) error {
+	return op.client.Customer.
+		Create().
+		SetName(op.name).
+		SetEmail(op.email).
+		OnConflictColumns(customer.FieldEmail).
+		UpdateName().
+		Exec(context.Background())
+}
+
+func (op *UpsertCustomerByEmail) Check(int) error {
+	return reference.CheckCustomerByEmail(context.Background(), op.db, op.email, op.name)
+}
+
+func (op *UpsertCustomerByEmail) Teardown() error {
+	var errs []error
+	for id := range op.renamed {
+		errs = append(errs, op.client.Customer.
+			UpdateOneID(id).
+			SetName(op.customers[id].Name).
+			Exec(context.Background()),
+		)
+	}
+
+	_, err := op.client.Customer.
+		Delete().
+		Where(customer.IDGT(op.lastID)).
+		Exec(context.Background())
+
+	return errors.Join(append(errs, err)...)
+}
+
+type UpsertCustomerByEmailHighConflict struct {
+	UpsertCustomerByEmail
+}
+
+func (op *UpsertCustomerByEmailHighConflict) Name() string {
+	return op.UpsertCustomerByEmail.Name() + " (High Conflict)"
+}
@@ -0,0 +1,143 @@
+// Package equivalence checks that every implementation leaves the database in the same state after running
+// the same operation. The test builds the implementations, runs every operation on a freshly restored database
+// and compares the rows they wrote. It needs a database and is skipped unless EQUIVALENCE_DSN is set.
+package equivalence
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// tables are the tables the catalog operations write to.
+var tables = []string{"customers", "products", "orders", "order_products"}
+
+// clockSkew widens the window in which a timestamp counts as written by the run.
+const clockSkew = time.Minute
+
+// State holds the canonical rows of every table, keyed by table name and row id.
+type State map[string]map[int]string
+
+// dump reads every table ordered by id and canonicalizes the rows.
+//
+// Timestamps written between start and end differ from run to run, so they are replaced by their rank
+// among the run timestamps of the same row: a row whose date and created_at come from one statement
+// gets "now#1" twice, a row whose columns were filled from separate clock reads gets "now#1" and "now#2".
+func dump(ctx context.Context, db *sql.DB, start, end time.Time) (State, error) {
+	state := make(State, len(tables))
+
+	for _, table := range tables {
+		rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT t.id, to_jsonb(t) FROM %s t ORDER BY t.id", table))
+		if err != nil {
+			return nil, err
+		}
+
+		state[table] = make(map[int]string)
+		for rows.Next() {
+			var id int
+			var raw []byte
+			if err := rows.Scan(&id, &raw); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			row, err := canonicalize(raw, start.Add(-clockSkew), end.Add(clockSkew))
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to canonicalize %s row %d: %w", table, id, err)
+			}
+			state[table][id] = row
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+func canonicalize(raw []byte, from, to time.Time) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var row map[string]any
+	if err := decoder.Decode(&row); err != nil {
+		return "", err
+	}
+
+	written := make(map[string]time.Time)
+	for column, value := range row {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err == nil && !t.Before(from) && !t.After(to) {
+			written[column] = t
+		}
+	}
+
+	var distinct []time.Time
+	for _, t := range written {
+		if !slices.ContainsFunc(distinct, t.Equal) {
+			distinct = append(distinct, t)
+		}
+	}
+	slices.SortFunc(distinct, func(a, b time.Time) int { return a.Compare(b) })
+
+	for column, t := range written {
+		row[column] = fmt.Sprintf("now#%d", slices.IndexFunc(distinct, t.Equal)+1)
+	}
+
+	canonical, err := json.Marshal(row)
+
+	return string(canonical), err
+}
+
+type difference struct {
+	table  string
+	id     int
+	values []string
+}
+
+// compare lists the rows that are not the same in every state.
+func compare(states []State) []difference {
+	var differences []difference
+
+	for _, table := range tables {
+		ids := make(map[int]struct{})
+		for _, state := range states {
+			for id := range state[table] {
+				ids[id] = struct{}{}
+			}
+		}
+
+		for _, id := range slices.Sorted(maps.Keys(ids)) {
+			values := make([]string, len(states))
+			for i, state := range states {
+				values[i] = "missing"
+				if row, ok := state[table][id]; ok {
+					values[i] = row
+				}
+			}
+
+			for _, v := range values[1:] {
+				if v != values[0] {
+					differences = append(differences, difference{table: table, id: id, values: values})
+					break
+				}
+			}
+		}
+	}
+
+	return differences
+}
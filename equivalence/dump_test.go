@@ -0,0 +1,105 @@
+package equivalence
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var (
+	runStart = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+	runEnd   = runStart.Add(time.Minute)
+)
+
+func mustCanonicalize(t *testing.T, raw string) string {
+	t.Helper()
+
+	row, err := canonicalize([]byte(raw), runStart, runEnd)
+	if err != nil {
+		t.Fatalf("canonicalize(%s) failed: %v", raw, err)
+	}
+
+	return row
+}
+
+// Both ends of the window count as written by the run, a nanosecond outside does not.
+func TestCanonicalizeWindowBounds(t *testing.T) {
+	for raw, want := range map[string]string{
+		`{"t": "2026-01-01T12:00:00Z"}`:           `{"t":"now#1"}`,
+		`{"t": "2026-01-01T12:01:00Z"}`:           `{"t":"now#1"}`,
+		`{"t": "2026-01-01T11:59:59.999999999Z"}`: `{"t":"2026-01-01T11:59:59.999999999Z"}`,
+		`{"t": "2026-01-01T12:01:00.000000001Z"}`: `{"t":"2026-01-01T12:01:00.000000001Z"}`,
+	} {
+		if got := mustCanonicalize(t, raw); got != want {
+			t.Errorf("canonicalize(%s) = %s, want %s", raw, got, want)
+		}
+	}
+}
+
+// Ranks depend on the instants only, not on column names or time zones,
+// so an implementation that writes created_at and date in one statement
+// matches another one doing the same a few seconds later.
+func TestCanonicalizeRanks(t *testing.T) {
+	one := mustCanonicalize(t, `{"created_at": "2026-01-01T12:00:10+00:00", "date": "2026-01-01T14:00:10+02:00"}`)
+	other := mustCanonicalize(t, `{"created_at": "2026-01-01T12:00:50Z", "date": "2026-01-01T12:00:50Z"}`)
+	if one != other || one != `{"created_at":"now#1","date":"now#1"}` {
+		t.Errorf("one statement ranked as %s and %s", one, other)
+	}
+
+	// Separate clock reads keep their order, whichever column comes first.
+	if got := mustCanonicalize(t, `{"a": "2026-01-01T12:00:40Z", "b": "2026-01-01T12:00:20Z"}`); got != `{"a":"now#2","b":"now#1"}` {
+		t.Errorf("separate clock reads ranked as %s", got)
+	}
+}
+
+// Ids and NUMERIC values beyond float64 precision must not collapse into equal rows.
+func TestCanonicalizeExactNumbers(t *testing.T) {
+	a := mustCanonicalize(t, `{"id": 9007199254740993, "price": 0.10}`)
+	b := mustCanonicalize(t, `{"id": 9007199254740992, "price": 0.1}`)
+	if a == b {
+		t.Errorf("distinct numbers canonicalized to the same row %s", a)
+	}
+}
+
+// Values that are not RFC 3339 timestamps are left alone, even inside the window.
+func TestCanonicalizeLeavesOtherStrings(t *testing.T) {
+	raw := `{"date": "2026-01-01", "status": "pending", "description": null}`
+	if got := mustCanonicalize(t, raw); got != `{"date":"2026-01-01","description":null,"status":"pending"}` {
+		t.Errorf("canonicalize(%s) = %s", raw, got)
+	}
+
+	if _, err := canonicalize([]byte(`[1, 2]`), runStart, runEnd); err == nil {
+		t.Error("canonicalize accepted a row that is not an object")
+	}
+}
+
+// Differences come out in table order and then by id, and tables outside
+// the catalog are not compared at all.
+func TestCompareOrder(t *testing.T) {
+	differences := compare([]State{
+		{"orders": {9: "a", 2: "a"}, "customers": {5: "a"}, "migrations": {1: "a"}},
+		{"orders": {9: "b", 2: "b"}, "customers": {5: "b"}, "migrations": {1: "b"}},
+		{"orders": {9: "a", 2: "a"}, "customers": {5: "a"}},
+	})
+
+	var got []string
+	for _, d := range differences {
+		got = append(got, d.table+"/"+d.values[1])
+		if len(d.values) != 3 {
+			t.Errorf("%s row %d has %d values, want one per state", d.table, d.id, len(d.values))
+		}
+	}
+	if want := []string{"customers/b", "orders/b", "orders/b"}; !slices.Equal(got, want) {
+		t.Errorf("compare reported %v, want %v", got, want)
+	}
+	if differences[1].id != 2 || differences[2].id != 9 {
+		t.Errorf("orders differences not sorted by id: %d, %d", differences[1].id, differences[2].id)
+	}
+}
+
+func TestCompareMissingRow(t *testing.T) {
+	differences := compare([]State{{"products": {1: "a"}}, {"products": {}}})
+	if len(differences) != 1 || !slices.Equal(differences[0].values, []string{"a", "missing"}) {
+		t.Errorf("compare reported %+v, want the row against missing", differences)
+	}
+}
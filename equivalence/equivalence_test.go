@@ -0,0 +1,182 @@
+package equivalence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/isolation"
+)
+
+const (
+	module = "github.com/yahn1ukov/go-orm-sql-efficiency"
+
+	// maxDifferences limits the rows reported per operation and table.
+	maxDifferences = 5
+)
+
+type implementation struct {
+	name       string
+	binary     string
+	operations []string
+}
+
+func (impl implementation) run(args ...string) ([]byte, error) {
+	cmd := exec.Command(impl.binary, args...)
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, stderr.String())
+	}
+
+	return out, nil
+}
+
+// env returns the value of the environment variable, or fallback when it is unset.
+func env(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+
+	return fallback
+}
+
+// TestEquivalence runs every operation in every implementation that has it, each time on a database freshly
+// restored by the isolation strategy and without the teardown, so the rows it wrote can be dumped and compared.
+//
+// It is configured with EQUIVALENCE_DSN, EQUIVALENCE_ISOLATION (reseed, snapshot or template),
+// EQUIVALENCE_OPERATIONS, EQUIVALENCE_ITERATIONS and EQUIVALENCE_IMPLEMENTATIONS.
+func TestEquivalence(t *testing.T) {
+	dsn := os.Getenv("EQUIVALENCE_DSN")
+	if dsn == "" {
+		t.Skip("EQUIVALENCE_DSN is not set")
+	}
+
+	strategy := env("EQUIVALENCE_ISOLATION", "snapshot")
+	if strategy == "none" {
+		t.Fatalf("isolation strategy \"none\" does not restore the database between runs")
+	}
+
+	operations := env("EQUIVALENCE_OPERATIONS", "")
+	iterations := env("EQUIVALENCE_ITERATIONS", "10")
+	if _, err := strconv.Atoi(iterations); err != nil {
+		t.Fatalf("invalid EQUIVALENCE_ITERATIONS: %v", err)
+	}
+
+	dir := t.TempDir()
+
+	var implementations []implementation
+	for _, name := range strings.Split(env("EQUIVALENCE_IMPLEMENTATIONS", "sql,gorm,ent,pgx"), ",") {
+		impl := implementation{name: name, binary: filepath.Join(dir, name)}
+
+		if out, err := exec.Command("go", "build", "-o", impl.binary, module+"/"+name).CombinedOutput(); err != nil {
+			t.Fatalf("failed to build %s: %v\n%s", name, err, out)
+		}
+
+		out, err := impl.run("-dsn", dsn, "-operations", operations, "-list")
+		if err != nil {
+			t.Fatalf("failed to list operations of %s: %v", name, err)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if line != "" {
+				impl.operations = append(impl.operations, line)
+			}
+		}
+
+		implementations = append(implementations, impl)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+
+	t.Cleanup(func() {
+		if err := isolation.Apply(ctx, strategy, dsn); err != nil {
+			t.Errorf("failed to restore database: %v", err)
+		}
+	})
+
+	var operationNames []string
+	for _, impl := range implementations {
+		for _, op := range impl.operations {
+			if !slices.Contains(operationNames, op) {
+				operationNames = append(operationNames, op)
+			}
+		}
+	}
+
+	for _, op := range operationNames {
+		t.Run(op, func(t *testing.T) {
+			var compared []string
+			var states []State
+			for _, impl := range implementations {
+				if !slices.Contains(impl.operations, op) {
+					t.Logf("not implemented by %s", impl.name)
+					continue
+				}
+
+				start := time.Now()
+				if _, err := impl.run(
+					"-dsn", dsn,
+					"-isolation", strategy,
+					"-operations", "^"+regexp.QuoteMeta(op)+"$",
+					"-iterations", iterations,
+					"-teardown=false",
+				); err != nil {
+					t.Fatalf("failed to run with %s: %v", impl.name, err)
+				}
+				end := time.Now()
+
+				state, err := dump(ctx, db, start, end)
+				if err != nil {
+					t.Fatalf("failed to dump state after %s: %v", impl.name, err)
+				}
+
+				compared = append(compared, impl.name)
+				states = append(states, state)
+			}
+
+			differences := compare(states)
+			if len(differences) == 0 {
+				return
+			}
+
+			t.Errorf("%d rows differ between %s", len(differences), strings.Join(compared, ", "))
+
+			shown := make(map[string]int)
+			for _, d := range differences {
+				if shown[d.table]++; shown[d.table] > maxDifferences {
+					continue
+				}
+
+				var values strings.Builder
+				for i, name := range compared {
+					fmt.Fprintf(&values, "\n    %-6s %s", name+":", d.values[i])
+				}
+				t.Errorf("%s id %d:%s", d.table, d.id, values.String())
+			}
+			for _, table := range tables {
+				if n := shown[table]; n > maxDifferences {
+					t.Errorf("%s: %d more rows differ", table, n-maxDifferences)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// CreateProductsInBulk imports products with CreateInBatches, which inserts BatchSize rows per statement
+// and wraps the batches in one transaction unless the default transaction is skipped.
+type CreateProductsInBulk struct {
+	BatchSize int
+	CreateProduct
+}
+
+func (op *CreateProductsInBulk) Name() string {
+	return "Create Products in Bulk"
+}
+
+func (op *CreateProductsInBulk) Rows() int {
+	return utils.BulkProducts
+}
+
+// BeforeEach deletes the products of the previous iteration.
+func (op *CreateProductsInBulk) BeforeEach(int) error {
+	return op.Teardown()
+}
+
+func (op *CreateProductsInBulk) Execute(iteration int) error {
+	products := make([]models.Product, utils.BulkProducts)
+	for i := range products {
+		products[i] = models.Product{
+			Name:        fmt.Sprintf("Product_%d_%d", iteration, i),
+			Description: &productDescription,
+			Price:       productPrice,
+			Stock:       100,
+		}
+	}
+
+	return op.db.
+		CreateInBatches(products, op.BatchSize).
+		Error
+}
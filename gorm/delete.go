@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/gorm/clause"
+)
+
+// customerDelete is embedded by the GORM delete operations. The history is created and checked on the
+// connection pool underneath GORM.
+type customerDelete struct {
+	history dataset.History
+	utils.WALVolume
+	GORM
+}
+
+func (d *customerDelete) BeforeEach(iteration int) error {
+	return d.check(func(ctx context.Context, db *sql.DB) error {
+		var err error
+		d.history, err = dataset.CreateCustomerHistory(
+			ctx,
+			db,
+			fmt.Sprintf("Customer_%d", iteration),
+			fmt.Sprintf("customer_%d@history.test", iteration),
+			orderQuantity,
+		)
+		if err != nil {
+			return err
+		}
+
+		return d.Start(ctx, db)
+	})
+}
+
+func (d *customerDelete) Rows() int {
+	return dataset.HistoryRows
+}
+
+func (d *customerDelete) Check(int) error {
+	return d.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckCustomerDeleted(ctx, db, d.history.CustomerID, d.history.FirstOrderID, d.history.LastOrderID)
+	})
+}
+
+func (d *customerDelete) AfterEach(int) error {
+	return d.check(d.Stop)
+}
+
+// DeleteCustomerWithOrdersCascade deletes only the customer and leaves the orders and their lines to the
+// ON DELETE CASCADE foreign keys.
+type DeleteCustomerWithOrdersCascade struct {
+	customerDelete
+}
+
+func (op *DeleteCustomerWithOrdersCascade) Name() string {
+	return "Delete Customer with Orders (Cascade)"
+}
+
+func (op *DeleteCustomerWithOrdersCascade) Execute(int) error {
+	return op.db.
+		Delete(&models.Customer{ID: op.history.CustomerID}).
+		Error
+}
+
+// DeleteCustomerWithOrdersAssociations lets GORM delete the orders before the customer. GORM only follows
+// the direct associations, so the order products are still removed by the cascade of the orders.
+type DeleteCustomerWithOrdersAssociations struct {
+	customerDelete
+}
+
+func (op *DeleteCustomerWithOrdersAssociations) Name() string {
+	return "Delete Customer with Orders (Associations)"
+}
+
+func (op *DeleteCustomerWithOrdersAssociations) Execute(int) error {
+	return op.db.
+		Select(clause.Associations).
+		Delete(&models.Customer{ID: op.history.CustomerID}).
+		Error
+}
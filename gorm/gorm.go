@@ -1,22 +1,48 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log"
+	"slices"
+	"sync/atomic"
 	"time"
 
+	"github.com/yahn1ukov/go-orm-sql-efficiency/bootstrap"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/migrations"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
+const (
+	productPrice  models.Money = 9999
+	orderQuantity              = 2
+)
+
+var productDescription = "Test product"
+
 type GORM struct {
 	db *gorm.DB
 }
 
+// check runs a reference check, or a fixture shared with the other implementations, on the connection pool
+// underneath GORM.
+func (g GORM) check(fn func(context.Context, *sql.DB) error) error {
+	db, err := g.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return fn(context.Background(), db)
+}
+
 type CreateProduct struct {
+	lastID int
 	GORM
 }
 
@@ -28,15 +54,32 @@ func (op *CreateProduct) Execute(iteration int) error {
 	return op.db.
 		Create(&models.Product{
 			Name:        fmt.Sprintf("Product_%d", iteration),
-			Description: "Test product",
-			Price:       99.99,
+			Description: &productDescription,
+			Price:       productPrice,
 			Stock:       100,
 		}).
 		Error
 }
 
+func (op *CreateProduct) Setup() error {
+	return op.db.
+		Model(&models.Product{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&op.lastID).
+		Error
+}
+
+func (op *CreateProduct) Teardown() error {
+	return op.db.
+		Where("id > ?", op.lastID).
+		Delete(&models.Product{}).
+		Error
+}
+
 type GetCustomerByID struct {
-	ID int
+	IDs    utils.Generator
+	id     int
+	result models.Customer
 	GORM
 }
 
@@ -45,15 +88,38 @@ func (op *GetCustomerByID) Name() string {
 }
 
 func (op *GetCustomerByID) Execute(int) error {
-	var customer models.Customer
+	op.id = op.IDs.Next()
+	op.result = models.Customer{}
 	return op.db.
-		First(&customer, op.ID).
+		First(&op.result, op.id).
+		Error
+}
+
+func (op *GetCustomerByID) Check(int) error {
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckCustomer(ctx, db, op.id, op.result)
+	})
+}
+
+type GetProductByID struct {
+	IDs utils.Generator
+	GORM
+}
+
+func (op *GetProductByID) Name() string {
+	return "Get Product by ID"
+}
+
+func (op *GetProductByID) Execute(int) error {
+	var product models.Product
+	return op.db.
+		First(&product, op.IDs.Next()).
 		Error
 }
 
 type UpdateProductByID struct {
-	ID    int
-	Price float64
+	IDs   utils.Generator
+	Price models.Money
 	GORM
 }
 
@@ -64,7 +130,7 @@ func (op *UpdateProductByID) Name() string {
 func (op *UpdateProductByID) Execute(int) error {
 	return op.db.
 		Model(&models.Product{}).
-		Where("id = ?", op.ID).
+		Where("id = ?", op.IDs.Next()).
 		Update("price", op.Price).
 		Error
 }
@@ -77,6 +143,10 @@ func (op *DeleteProductByName) Name() string {
 	return "Delete Product by Name"
 }
 
+func (op *DeleteProductByName) BeforeEach(iteration int) error {
+	return (&CreateProduct{GORM: op.GORM}).Execute(iteration)
+}
+
 func (op *DeleteProductByName) Execute(iteration int) error {
 	return op.db.
 		Where("name = ?", fmt.Sprintf("Product_%d", iteration)).
@@ -85,8 +155,9 @@ func (op *DeleteProductByName) Execute(iteration int) error {
 }
 
 type CreateOrderWithProductsByCustomerID struct {
-	CustomerID int
-	ProductID  int
+	CustomerIDs utils.Generator
+	ProductIDs  utils.Generator
+	lastID      int
 	GORM
 }
 
@@ -95,18 +166,21 @@ func (op *CreateOrderWithProductsByCustomerID) Name() string {
 }
 
 func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
+	customerID := op.CustomerIDs.Next()
+	productID := op.ProductIDs.Next()
+
 	return op.db.
 		Transaction(func(tx *gorm.DB) error {
 			return tx.
 				Create(&models.Order{
-					CustomerID: op.CustomerID,
+					CustomerID: customerID,
 					Date:       time.Now(),
-					Total:      199.98,
+					Total:      productPrice.Mul(orderQuantity),
 					Products: []models.OrderProduct{
 						{
-							ProductID: op.ProductID,
-							Quantity:  2,
-							Price:     99.99,
+							ProductID: productID,
+							Quantity:  orderQuantity,
+							Price:     productPrice,
 						},
 					},
 				}).
@@ -114,8 +188,25 @@ func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
 		})
 }
 
+func (op *CreateOrderWithProductsByCustomerID) Setup() error {
+	return op.db.
+		Model(&models.Order{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&op.lastID).
+		Error
+}
+
+func (op *CreateOrderWithProductsByCustomerID) Teardown() error {
+	return op.db.
+		Where("id > ?", op.lastID).
+		Delete(&models.Order{}).
+		Error
+}
+
 type GetCustomerStatsByID struct {
-	CustomerID int
+	CustomerIDs utils.Generator
+	customerID  int
+	result      reference.CustomerStats
 	GORM
 }
 
@@ -124,21 +215,25 @@ func (op *GetCustomerStatsByID) Name() string {
 }
 
 func (op *GetCustomerStatsByID) Execute(int) error {
-	var result struct {
-		TotalOrders int64
-		TotalSpent  float64
-	}
-
+	op.customerID = op.CustomerIDs.Next()
+	op.result = reference.CustomerStats{}
 	return op.db.
 		Model(&models.Order{}).
 		Select("COUNT(*) as total_orders, COALESCE(SUM(total), 0) as total_spent").
-		Where("customer_id = ?", op.CustomerID).
-		Scan(&result).
+		Where("customer_id = ?", op.customerID).
+		Scan(&op.result).
 		Error
 }
 
+func (op *GetCustomerStatsByID) Check(int) error {
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckCustomerStats(ctx, db, op.customerID, op.result)
+	})
+}
+
 type GetProductSalesByLimit struct {
-	Limit int
+	Limit   int
+	results []reference.ProductSales
 	GORM
 }
 
@@ -147,14 +242,7 @@ func (op *GetProductSalesByLimit) Name() string {
 }
 
 func (op *GetProductSalesByLimit) Execute(int) error {
-	type Result struct {
-		ProductID   uint
-		ProductName string
-		TotalSales  int64
-		Revenue     float64
-	}
-
-	var results []Result
+	op.results = nil
 	return op.db.
 		Model(&models.OrderProduct{}).
 		Select("product_id, products.name as product_name, SUM(quantity) as total_sales, SUM(order_products.price * quantity) as revenue").
@@ -162,61 +250,332 @@ func (op *GetProductSalesByLimit) Execute(int) error {
 		Group("product_id, products.name").
 		Order("revenue DESC").
 		Limit(op.Limit).
-		Scan(&results).
+		Scan(&op.results).
 		Error
 }
 
-func main() {
-	dsn := "host=localhost user= password= dbname= port=5432 sslmode=disable"
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+func (op *GetProductSalesByLimit) Check(int) error {
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckProductSales(ctx, db, op.Limit, op.results)
 	})
-	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
-	}
-
-	var customer models.Customer
-	if err = db.First(&customer).Error; err != nil {
-		log.Fatalf("failed to get customer: %v", err)
-	}
-
-	var product models.Product
-	if err = db.First(&product).Error; err != nil {
-		log.Fatalf("failed to get product: %v", err)
-	}
-
-	iterations := 10000
+}
 
+// catalog builds every operation on the given GORM handle, each with its own parameter generators.
+func catalog(db *gorm.DB, queries *atomic.Int64, config utils.Config, generator func([]int, uint64) utils.Generator, customerIDs, productIDs []int) []utils.Operation {
 	operations := []utils.Operation{
 		&CreateProduct{
 			GORM: GORM{db},
 		},
 		&GetCustomerByID{
-			ID:   customer.ID,
+			IDs:  generator(customerIDs, 0),
 			GORM: GORM{db},
 		},
+		&GetProductByID{
+			IDs:  generator(productIDs, 1),
+			GORM: GORM{db},
+		},
+		&GetProductByIDFloat64{
+			GetProductByID: GetProductByID{
+				IDs:  generator(productIDs, 1),
+				GORM: GORM{db},
+			},
+		},
+		&GetProductByIDNullString{
+			GetProductByID: GetProductByID{
+				IDs:  generator(productIDs, 1),
+				GORM: GORM{db},
+			},
+		},
+		&GetProductByIDCoalesce{
+			GetProductByID: GetProductByID{
+				IDs:  generator(productIDs, 1),
+				GORM: GORM{db},
+			},
+		},
 		&UpdateProductByID{
-			ID:    product.ID,
+			IDs:   generator(productIDs, 1),
+			Price: 8999,
+			GORM:  GORM{db},
+		},
+		&UpdateProductByIDFloat64{
+			IDs:   generator(productIDs, 1),
 			Price: 89.99,
 			GORM:  GORM{db},
 		},
+		&UpdateProductByExpression{
+			IDs: generator(productIDs, 1),
+			productUpdate: productUpdate{
+				GORM: GORM{db},
+			},
+		},
+		&UpdateProductsByIDs{
+			IDs: generator(productIDs, 1),
+			productUpdate: productUpdate{
+				GORM: GORM{db},
+			},
+		},
+		&UpdateProductsByPriceBand{
+			productUpdate: productUpdate{
+				GORM: GORM{db},
+			},
+		},
 		&CreateOrderWithProductsByCustomerID{
-			CustomerID: customer.ID,
-			ProductID:  product.ID,
-			GORM:       GORM{db},
+			CustomerIDs: generator(customerIDs, 0),
+			ProductIDs:  generator(productIDs, 1),
+			GORM:        GORM{db},
+		},
+		&CreateOrderWithProductsByCustomerIDFloat64{
+			CreateOrderWithProductsByCustomerID: CreateOrderWithProductsByCustomerID{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				GORM:        GORM{db},
+			},
 		},
 		&GetCustomerStatsByID{
-			CustomerID: customer.ID,
-			GORM:       GORM{db},
+			CustomerIDs: generator(customerIDs, 0),
+			GORM:        GORM{db},
+		},
+		&GetCustomerStatsByIDFloat64{
+			GetCustomerStatsByID: GetCustomerStatsByID{
+				CustomerIDs: generator(customerIDs, 0),
+				GORM:        GORM{db},
+			},
 		},
 		&GetProductSalesByLimit{
 			Limit: 10,
 			GORM:  GORM{db},
 		},
+		&GetProductSalesByLimitFloat64{
+			GetProductSalesByLimit: GetProductSalesByLimit{
+				Limit: 10,
+				GORM:  GORM{db},
+			},
+		},
+		&LoadCustomerGraphNPlusOne{
+			customerGraph: customerGraph{
+				CustomerIDs: generator(customerIDs, 0),
+				Queries:     queries,
+				GORM:        GORM{db},
+			},
+		},
+		&LoadCustomerGraphPreload{
+			customerGraph: customerGraph{
+				CustomerIDs: generator(customerIDs, 0),
+				Queries:     queries,
+				GORM:        GORM{db},
+			},
+		},
+		&LoadCustomerGraphPreloadJoins{
+			customerGraph: customerGraph{
+				CustomerIDs: generator(customerIDs, 0),
+				Queries:     queries,
+				GORM:        GORM{db},
+			},
+		},
+		&PaginateProductsOffset{
+			productWalk: productWalk{
+				GORM: GORM{db},
+			},
+		},
+		&PaginateProductsKeyset{
+			productWalk: productWalk{
+				GORM: GORM{db},
+			},
+		},
+		&SearchProducts{
+			Filters: config.FilterGenerator(2),
+			GORM:    GORM{db},
+		},
+		&StreamOrderProductsRows{
+			orderProductStream: orderProductStream{
+				OrderProductStream: reference.OrderProductStream{Limit: config.StreamRows},
+				GORM:               GORM{db},
+			},
+		},
+		&StreamOrderProductsFindInBatches{
+			BatchSize: config.BatchSize,
+			orderProductStream: orderProductStream{
+				OrderProductStream: reference.OrderProductStream{Limit: config.StreamRows},
+				GORM:               GORM{db},
+			},
+		},
+		&ReserveInventory{
+			CustomerIDs: generator(customerIDs, 0),
+			ProductIDs:  generator(productIDs, 1),
+			Workers:     config.Workers,
+			GORM:        GORM{db},
+		},
+		&ReserveInventoryUnordered{
+			ReserveInventory: ReserveInventory{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				Workers:     config.Workers,
+				GORM:        GORM{db},
+			},
+		},
+		&UpsertCustomerByEmail{
+			CustomerIDs:   generator(customerIDs, 0),
+			ConflictRatio: 0.1,
+			GORM:          GORM{db},
+		},
+		&UpsertCustomerByEmailHighConflict{
+			UpsertCustomerByEmail: UpsertCustomerByEmail{
+				CustomerIDs:   generator(customerIDs, 0),
+				ConflictRatio: 0.9,
+				GORM:          GORM{db},
+			},
+		},
 		&DeleteProductByName{
 			GORM: GORM{db},
 		},
+		&DeleteCustomerWithOrdersCascade{
+			customerDelete: customerDelete{
+				GORM: GORM{db},
+			},
+		},
+		&DeleteCustomerWithOrdersAssociations{
+			customerDelete: customerDelete{
+				GORM: GORM{db},
+			},
+		},
+		&CreateProductsInBulk{
+			BatchSize: config.BatchSize,
+			CreateProduct: CreateProduct{
+				GORM: GORM{db},
+			},
+		},
+	}
+
+	// The same operations on a session that prepares every statement once and reuses it.
+	prepared := GORM{db.Session(&gorm.Session{PrepareStmt: true})}
+	operations = append(
+		operations,
+		utils.Variant{
+			Operation: &CreateProduct{GORM: prepared},
+			Suffix:    "PrepareStmt",
+		},
+		utils.Variant{
+			Operation: &GetCustomerByID{IDs: generator(customerIDs, 0), GORM: prepared},
+			Suffix:    "PrepareStmt",
+		},
+		utils.Variant{
+			Operation: &GetProductByID{IDs: generator(productIDs, 1), GORM: prepared},
+			Suffix:    "PrepareStmt",
+		},
+		utils.Variant{
+			Operation: &UpdateProductByID{IDs: generator(productIDs, 1), Price: 8999, GORM: prepared},
+			Suffix:    "PrepareStmt",
+		},
+		utils.Variant{
+			Operation: &CreateOrderWithProductsByCustomerID{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				GORM:        prepared,
+			},
+			Suffix: "PrepareStmt",
+		},
+		utils.Variant{
+			Operation: &GetCustomerStatsByID{CustomerIDs: generator(customerIDs, 0), GORM: prepared},
+			Suffix:    "PrepareStmt",
+		},
+		utils.Variant{
+			Operation: &GetProductSalesByLimit{Limit: 10, GORM: prepared},
+			Suffix:    "PrepareStmt",
+		},
+		utils.Variant{
+			Operation: &DeleteProductByName{GORM: prepared},
+			Suffix:    "PrepareStmt",
+		},
+	)
+
+	return operations
+}
+
+func main() {
+	config := utils.ParseConfig()
+
+	combinations, err := parseMatrix(*matrix)
+	if err != nil {
+		log.Fatalf("failed to build GORM configuration matrix: %v", err)
 	}
 
-	utils.PrintResult(operations, iterations)
+	// The catalog is the same for every combination, so -list prints it once.
+	if config.List {
+		combinations = combinations[:1]
+	}
+
+	for _, combination := range combinations {
+		run(config, combination)
+	}
+}
+
+// run measures the catalog with one GORM configuration. Every combination restores the database with the
+// isolation strategy and opens a connection pool of its own, so it neither measures the rows an earlier
+// combination wrote nor inherits the statements an earlier one prepared.
+func run(config utils.Config, combination combination) {
+	ctx := context.Background()
+
+	schema, err := bootstrap.Start(ctx, config)
+	if err != nil {
+		log.Fatalf("failed to prepare database: %v", err)
+	}
+
+	sqlDB, err := config.OpenDB(schema.DSN)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), combination.Config())
+	if err != nil {
+		log.Fatalf("failed to open GORM: %v", err)
+	}
+
+	// PrepareStmt wraps the pool in a statement cache, which is closed before the next combination runs.
+	if stmts, ok := db.ConnPool.(*gorm.PreparedStmtDB); ok {
+		defer stmts.Close()
+	}
+
+	if err := migrations.Check(ctx, sqlDB); err != nil {
+		log.Fatalf("failed to check schema version: %v", err)
+	}
+
+	metadata, err := dataset.Describe(ctx, sqlDB)
+	if err != nil {
+		log.Fatalf("failed to describe dataset: %v", err)
+	}
+
+	var customerIDs []int
+	if err = db.Model(&models.Customer{}).Order("id").Pluck("id", &customerIDs).Error; err != nil {
+		log.Fatalf("failed to get customers: %v", err)
+	}
+
+	var productIDs []int
+	if err = db.Model(&models.Product{}).Order("id").Pluck("id", &productIDs).Error; err != nil {
+		log.Fatalf("failed to get products: %v", err)
+	}
+
+	generator := func(ids []int, stream uint64) utils.Generator {
+		g, err := config.Generator(ids, stream)
+		if err != nil {
+			log.Fatalf("failed to create generator: %v", err)
+		}
+
+		return g
+	}
+
+	queries, err := countQueries(db)
+	if err != nil {
+		log.Fatalf("failed to register query counter: %v", err)
+	}
+
+	labels := slices.Concat(
+		config.Labels(),
+		schema.Labels(),
+		metadata.Labels(),
+		[]utils.Label{{Name: "GORM Config", Value: combination.String()}},
+	)
+	if err := config.Run(catalog(db, queries, config, generator, customerIDs, productIDs), labels...); err != nil {
+		log.Fatalf("failed to run operations: %v", err)
+	}
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/gorm"
+)
+
+// countQueries counts the queries GORM runs on db, including the ones Preload issues for every association.
+func countQueries(db *gorm.DB) (*atomic.Int64, error) {
+	var queries atomic.Int64
+	err := db.
+		Callback().
+		Query().
+		After("gorm:query").
+		Register("benchmark:count_queries", func(*gorm.DB) {
+			queries.Add(1)
+		})
+
+	return &queries, err
+}
+
+// customerGraph holds the customers of an iteration. Queries is the counter countQueries registered
+// on its db, so it sees the queries Preload issues as well.
+type customerGraph struct {
+	CustomerIDs utils.Generator
+	Queries     *atomic.Int64
+	ids         []int
+	customers   []models.Customer
+	GORM
+}
+
+func (g *customerGraph) BeforeEach(int) error {
+	var err error
+	g.ids, err = reference.NextGraphCustomers(g.CustomerIDs)
+
+	return err
+}
+
+func (g *customerGraph) RoundTrips() int64 {
+	return g.Queries.Load()
+}
+
+func (g *customerGraph) Check(int) error {
+	return g.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckCustomerGraph(ctx, db, g.ids, g.customers)
+	})
+}
+
+type LoadCustomerGraphNPlusOne struct {
+	customerGraph
+}
+
+func (op *LoadCustomerGraphNPlusOne) Name() string {
+	return "Load Customer Graph (N+1)"
+}
+
+func (op *LoadCustomerGraphNPlusOne) Execute(int) error {
+	op.customers = make([]models.Customer, len(op.ids))
+	for i, id := range op.ids {
+		c := &op.customers[i]
+		if err := op.db.First(c, id).Error; err != nil {
+			return err
+		}
+
+		if err := op.db.
+			Where("customer_id = ?", c.ID).
+			Order("id").
+			Find(&c.Orders).
+			Error; err != nil {
+			return err
+		}
+
+		for j := range c.Orders {
+			o := &c.Orders[j]
+			if err := op.db.
+				Where("order_id = ?", o.ID).
+				Order("id").
+				Find(&o.Products).
+				Error; err != nil {
+				return err
+			}
+
+			for k := range o.Products {
+				line := &o.Products[k]
+				if err := op.db.First(&line.Product, line.ProductID).Error; err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+type LoadCustomerGraphPreload struct {
+	customerGraph
+}
+
+func (op *LoadCustomerGraphPreload) Name() string {
+	return "Load Customer Graph (Preload)"
+}
+
+func (op *LoadCustomerGraphPreload) Execute(int) error {
+	op.customers = nil
+	return op.db.
+		Preload("Orders.Products.Product").
+		Where("id IN ?", op.ids).
+		Find(&op.customers).
+		Error
+}
+
+type LoadCustomerGraphPreloadJoins struct {
+	customerGraph
+}
+
+func (op *LoadCustomerGraphPreloadJoins) Name() string {
+	return "Load Customer Graph (Preload + Joins)"
+}
+
+// Execute preloads the orders and their line items, but joins the products into the line item query
+// instead of preloading them with a query of their own.
+func (op *LoadCustomerGraphPreloadJoins) Execute(int) error {
+	op.customers = nil
+	return op.db.
+		Preload("Orders").
+		Preload("Orders.Products", func(db *gorm.DB) *gorm.DB {
+			return db.Joins("Product")
+		}).
+		Where("id IN ?", op.ids).
+		Find(&op.customers).
+		Error
+}
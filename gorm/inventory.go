@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reservedProducts is the number of distinct products every checkout reserves.
+const reservedProducts = 3
+
+// errOutOfStock rolls back a checkout of a product without enough stock.
+var errOutOfStock = errors.New("out of stock")
+
+type checkout struct {
+	customerID int
+	productIDs []int
+}
+
+// ReserveInventory runs a checkout in each of Workers concurrent transactions: it locks the products,
+// verifies and decrements their stock, and places the order with totals computed from the locked prices.
+// A checkout of a product out of stock is rolled back and counted as rejected.
+// AfterEach restores the stock of the products and deletes the orders outside the timed section,
+// so every iteration reserves from the initial stock.
+type ReserveInventory struct {
+	CustomerIDs utils.Generator
+	ProductIDs  utils.Generator
+	Workers     int
+	checkouts   []checkout
+	stock       map[int]int
+	reserved    []int
+	lastOrderID int
+	utils.Contention
+	GORM
+}
+
+func (op *ReserveInventory) Name() string {
+	return "Reserve Inventory (Checkout)"
+}
+
+func (op *ReserveInventory) Setup() error {
+	if err := op.db.
+		Model(&models.Order{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&op.lastOrderID).
+		Error; err != nil {
+		return err
+	}
+
+	var products []models.Product
+	if err := op.db.Select("id", "stock").Find(&products).Error; err != nil {
+		return err
+	}
+
+	op.stock = make(map[int]int, len(products))
+	for _, p := range products {
+		op.stock[p.ID] = p.Stock
+	}
+
+	return nil
+}
+
+func (op *ReserveInventory) BeforeEach(int) error {
+	op.checkouts = make([]checkout, op.Workers)
+	op.reserved = op.reserved[:0]
+	for w := range op.checkouts {
+		productIDs, err := utils.NextDistinct(op.ProductIDs, min(reservedProducts, op.ProductIDs.Len()))
+		if err != nil {
+			return err
+		}
+
+		op.checkouts[w] = checkout{
+			customerID: op.CustomerIDs.Next(),
+			productIDs: productIDs,
+		}
+		op.reserved = append(op.reserved, productIDs...)
+	}
+
+	return nil
+}
+
+func (op *ReserveInventory) Execute(int) error {
+	return op.reserve(true)
+}
+
+// reserve runs the checkouts of the iteration concurrently. Ordered checkouts lock their products in id order,
+// so concurrent checkouts of the same products wait for each other instead of deadlocking.
+func (op *ReserveInventory) reserve(ordered bool) error {
+	return utils.Parallel(op.Workers, func(w int) error {
+		return op.Reserve(func() (bool, error) {
+			return op.checkout(op.checkouts[w], ordered)
+		})
+	})
+}
+
+func (op *ReserveInventory) checkout(c checkout, ordered bool) (bool, error) {
+	locking := clause.Locking{Strength: clause.LockingStrengthUpdate}
+
+	err := op.db.
+		Transaction(func(tx *gorm.DB) error {
+			var products []models.Product
+			if ordered {
+				if err := op.Lock(func() error {
+					return tx.
+						Clauses(locking).
+						Select("id", "price", "stock").
+						Where("id IN ?", c.productIDs).
+						Order("id").
+						Find(&products).
+						Error
+				}); err != nil {
+					return err
+				}
+			} else {
+				products = make([]models.Product, len(c.productIDs))
+				for i, id := range c.productIDs {
+					if err := op.Lock(func() error {
+						return tx.
+							Clauses(locking).
+							Select("id", "price", "stock").
+							Where("id = ?", id).
+							Take(&products[i]).
+							Error
+					}); err != nil {
+						return err
+					}
+				}
+			}
+
+			order := models.Order{
+				CustomerID: c.customerID,
+				Date:       time.Now(),
+			}
+			for _, p := range products {
+				if p.Stock < orderQuantity {
+					return errOutOfStock
+				}
+				order.Total += p.Price.Mul(orderQuantity)
+				order.Products = append(order.Products, models.OrderProduct{
+					ProductID: p.ID,
+					Quantity:  orderQuantity,
+					Price:     p.Price,
+				})
+			}
+
+			if err := tx.
+				Model(&models.Product{}).
+				Where("id IN ?", c.productIDs).
+				Update("stock", gorm.Expr("stock - ?", orderQuantity)).
+				Error; err != nil {
+				return err
+			}
+
+			return tx.
+				Create(&order).
+				Error
+		})
+	if errors.Is(err, errOutOfStock) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (op *ReserveInventory) Check(int) error {
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckInventory(ctx, db, op.stock, op.lastOrderID)
+	})
+}
+
+func (op *ReserveInventory) AfterEach(int) error {
+	stocks := make([]int, len(op.reserved))
+	for i, id := range op.reserved {
+		stocks[i] = op.stock[id]
+	}
+
+	if err := op.db.
+		Exec(
+			`UPDATE products p SET stock = s.stock
+            FROM unnest(?::int[], ?::int[]) AS s(id, stock)
+            WHERE p.id = s.id`,
+			pq.Array(op.reserved),
+			pq.Array(stocks),
+		).
+		Error; err != nil {
+		return err
+	}
+
+	return op.db.
+		Where("id > ?", op.lastOrderID).
+		Delete(&models.Order{}).
+		Error
+}
+
+// ReserveInventoryUnordered locks the products of a checkout one by one in the order they were drawn,
+// so concurrent checkouts sharing products can deadlock and are retried.
+type ReserveInventoryUnordered struct {
+	ReserveInventory
+}
+
+func (op *ReserveInventoryUnordered) Name() string {
+	return op.ReserveInventory.Name() + " (Unordered Locks)"
+}
+
+func (op *ReserveInventoryUnordered) Execute(int) error {
+	return op.reserve(false)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type setting struct {
+	key   string
+	label string
+	apply func(*gorm.Config)
+}
+
+// settings are the GORM options the configuration matrix varies. All of them are off in the baseline,
+// which only silences the logger.
+var settings = []setting{
+	{"skip-default-transaction", "SkipDefaultTransaction", func(c *gorm.Config) { c.SkipDefaultTransaction = true }},
+	{"prepare-stmt", "PrepareStmt", func(c *gorm.Config) { c.PrepareStmt = true }},
+	{"query-fields", "QueryFields", func(c *gorm.Config) { c.QueryFields = true }},
+	{"create-batch-size", "CreateBatchSize=100", func(c *gorm.Config) { c.CreateBatchSize = 100 }},
+	{"info-logger", "Logger=Info", func(c *gorm.Config) {
+		c.Logger = logger.New(log.New(io.Discard, "", log.LstdFlags), logger.Config{LogLevel: logger.Info})
+	}},
+}
+
+var matrix = flag.String(
+	"gorm-matrix",
+	"",
+	"comma separated GORM settings to vary, or all: skip-default-transaction, prepare-stmt, query-fields, create-batch-size or info-logger",
+)
+
+type combination []setting
+
+func (c combination) Config() *gorm.Config {
+	config := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	}
+	for _, s := range c {
+		s.apply(config)
+	}
+
+	return config
+}
+
+func (c combination) String() string {
+	if len(c) == 0 {
+		return "default"
+	}
+
+	labels := make([]string, len(c))
+	for i, s := range c {
+		labels[i] = s.label
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// parseMatrix returns every subset of the selected settings, starting with the baseline.
+func parseMatrix(selection string) ([]combination, error) {
+	var selected []setting
+	switch selection {
+	case "":
+	case "all":
+		selected = settings
+	default:
+		for _, key := range strings.Split(selection, ",") {
+			i := slices.IndexFunc(settings, func(s setting) bool { return s.key == key })
+			if i < 0 {
+				return nil, fmt.Errorf("unknown GORM setting \"%s\"", key)
+			}
+			if slices.ContainsFunc(selected, func(s setting) bool { return s.key == key }) {
+				return nil, fmt.Errorf("duplicate GORM setting \"%s\"", key)
+			}
+			selected = append(selected, settings[i])
+		}
+	}
+
+	result := make([]combination, 0, 1<<len(selected))
+	for mask := 0; mask < 1<<len(selected); mask++ {
+		var c combination
+		for i, s := range selected {
+			if mask&(1<<i) != 0 {
+				c = append(c, s)
+			}
+		}
+		result = append(result, c)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func labels(t *testing.T, selection string) []string {
+	t.Helper()
+
+	combinations, err := parseMatrix(selection)
+	if err != nil {
+		t.Fatalf("parseMatrix(%q) failed: %v", selection, err)
+	}
+
+	l := make([]string, len(combinations))
+	for i, c := range combinations {
+		l[i] = c.String()
+	}
+
+	return l
+}
+
+// Without -gorm-matrix the benchmark runs the baseline once, as before the matrix existed.
+func TestParseMatrixBaselineOnly(t *testing.T) {
+	if got := labels(t, ""); !slices.Equal(got, []string{"default"}) {
+		t.Errorf("empty selection = %q, want only the baseline", got)
+	}
+}
+
+// The selection order decides the order of the labels and of the combinations.
+func TestParseMatrixSelectionOrder(t *testing.T) {
+	want := []string{"default", "QueryFields", "PrepareStmt", "QueryFields, PrepareStmt"}
+	if got := labels(t, "query-fields,prepare-stmt"); !slices.Equal(got, want) {
+		t.Errorf("parseMatrix = %q, want %q", got, want)
+	}
+}
+
+func TestParseMatrixRejects(t *testing.T) {
+	for _, selection := range []string{"prepare-stmt,unknown", "prepare-stmt,", " prepare-stmt", "PrepareStmt", "prepare-stmt,prepare-stmt"} {
+		if _, err := parseMatrix(selection); err == nil {
+			t.Errorf("parseMatrix(%q) succeeded, want an error", selection)
+		}
+	}
+}
+
+// "all" covers every subset once, so each setting is on in exactly half of the runs.
+func TestParseMatrixAll(t *testing.T) {
+	combinations, err := parseMatrix("all")
+	if err != nil {
+		t.Fatalf("parseMatrix(\"all\") failed: %v", err)
+	}
+	if len(combinations) != 1<<len(settings) {
+		t.Fatalf("parseMatrix(\"all\") returned %d combinations, want %d", len(combinations), 1<<len(settings))
+	}
+
+	on := make(map[string]int)
+	for _, c := range combinations {
+		for _, s := range c {
+			on[s.key]++
+		}
+	}
+	for _, s := range settings {
+		if on[s.key] != len(combinations)/2 {
+			t.Errorf("%s is on in %d of %d combinations", s.key, on[s.key], len(combinations))
+		}
+	}
+}
+
+// Each setting changes only its own option, and building one combination's
+// config does not leak into the baseline built afterwards.
+func TestCombinationConfigIsolated(t *testing.T) {
+	baseline := combination(nil).Config()
+
+	for _, s := range settings {
+		config := combination{s}.Config()
+
+		changed := 0
+		if config.SkipDefaultTransaction != baseline.SkipDefaultTransaction {
+			changed++
+		}
+		if config.PrepareStmt != baseline.PrepareStmt {
+			changed++
+		}
+		if config.QueryFields != baseline.QueryFields {
+			changed++
+		}
+		if config.CreateBatchSize != baseline.CreateBatchSize {
+			changed++
+		}
+
+		// The logger is the only setting that leaves the flags alone.
+		want := 1
+		if s.key == "info-logger" {
+			want = 0
+		}
+		if changed != want {
+			t.Errorf("%s changes %d flags of the baseline config, want %d", s.key, changed, want)
+		}
+	}
+
+	after := combination(nil).Config()
+	if after.SkipDefaultTransaction || after.PrepareStmt || after.QueryFields || after.CreateBatchSize != 0 {
+		t.Errorf("baseline config changed after building the other combinations: %+v", after)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/gorm"
+)
+
+// The float64 variants map the same tables as the models package,
+// but with float64 prices and totals instead of models.Money.
+
+const productPriceFloat64 = 99.99
+
+type productFloat64 struct {
+	ID          int
+	Name        string
+	Description *string
+	Price       float64
+	Stock       int
+	CreatedAt   time.Time
+}
+
+func (productFloat64) TableName() string {
+	return "products"
+}
+
+type orderFloat64 struct {
+	ID         int
+	CustomerID int
+	Date       time.Time
+	Total      float64
+	Products   []orderProductFloat64 `gorm:"foreignKey:OrderID"`
+	CreatedAt  time.Time
+}
+
+func (orderFloat64) TableName() string {
+	return "orders"
+}
+
+type orderProductFloat64 struct {
+	ID        int
+	OrderID   int
+	ProductID int
+	Quantity  int
+	Price     float64
+}
+
+func (orderProductFloat64) TableName() string {
+	return "order_products"
+}
+
+type GetProductByIDFloat64 struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDFloat64) Name() string {
+	return op.GetProductByID.Name() + " (float64)"
+}
+
+func (op *GetProductByIDFloat64) Execute(int) error {
+	var product productFloat64
+	return op.db.
+		First(&product, op.IDs.Next()).
+		Error
+}
+
+type UpdateProductByIDFloat64 struct {
+	IDs   utils.Generator
+	Price float64
+	GORM
+}
+
+func (op *UpdateProductByIDFloat64) Name() string {
+	return "Update Product Price by ID (float64)"
+}
+
+func (op *UpdateProductByIDFloat64) Execute(int) error {
+	return op.db.
+		Model(&productFloat64{}).
+		Where("id = ?", op.IDs.Next()).
+		Update("price", op.Price).
+		Error
+}
+
+type CreateOrderWithProductsByCustomerIDFloat64 struct {
+	CreateOrderWithProductsByCustomerID
+}
+
+func (op *CreateOrderWithProductsByCustomerIDFloat64) Name() string {
+	return op.CreateOrderWithProductsByCustomerID.Name() + " (float64)"
+}
+
+func (op *CreateOrderWithProductsByCustomerIDFloat64) Execute(int) error {
+	customerID := op.CustomerIDs.Next()
+	productID := op.ProductIDs.Next()
+
+	return op.db.
+		Transaction(func(tx *gorm.DB) error {
+			return tx.
+				Create(&orderFloat64{
+					CustomerID: customerID,
+					Date:       time.Now(),
+					Total:      productPriceFloat64 * orderQuantity,
+					Products: []orderProductFloat64{
+						{
+							ProductID: productID,
+							Quantity:  orderQuantity,
+							Price:     productPriceFloat64,
+						},
+					},
+				}).
+				Error
+		})
+}
+
+type GetCustomerStatsByIDFloat64 struct {
+	result struct {
+		TotalOrders int64
+		TotalSpent  float64
+	}
+	GetCustomerStatsByID
+}
+
+func (op *GetCustomerStatsByIDFloat64) Name() string {
+	return op.GetCustomerStatsByID.Name() + " (float64)"
+}
+
+func (op *GetCustomerStatsByIDFloat64) Execute(int) error {
+	op.customerID = op.CustomerIDs.Next()
+	op.result.TotalOrders, op.result.TotalSpent = 0, 0
+	return op.db.
+		Model(&orderFloat64{}).
+		Select("COUNT(*) as total_orders, COALESCE(SUM(total), 0) as total_spent").
+		Where("customer_id = ?", op.customerID).
+		Scan(&op.result).
+		Error
+}
+
+func (op *GetCustomerStatsByIDFloat64) Check(int) error {
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckCustomerStats(ctx, db, op.customerID, reference.CustomerStats{
+			TotalOrders: op.result.TotalOrders,
+			TotalSpent:  models.MoneyFromFloat(op.result.TotalSpent),
+		})
+	})
+}
+
+type productSalesFloat64 struct {
+	ProductID   int
+	ProductName string
+	TotalSales  int64
+	Revenue     float64
+}
+
+type GetProductSalesByLimitFloat64 struct {
+	results []productSalesFloat64
+	GetProductSalesByLimit
+}
+
+func (op *GetProductSalesByLimitFloat64) Name() string {
+	return op.GetProductSalesByLimit.Name() + " (float64)"
+}
+
+func (op *GetProductSalesByLimitFloat64) Execute(int) error {
+	op.results = nil
+	return op.db.
+		Model(&orderProductFloat64{}).
+		Select("product_id, products.name as product_name, SUM(quantity) as total_sales, SUM(order_products.price * quantity) as revenue").
+		Joins("JOIN products ON products.id = order_products.product_id").
+		Group("product_id, products.name").
+		Order("revenue DESC").
+		Limit(op.Limit).
+		Scan(&op.results).
+		Error
+}
+
+func (op *GetProductSalesByLimitFloat64) Check(int) error {
+	results := make([]reference.ProductSales, len(op.results))
+	for i, r := range op.results {
+		results[i] = reference.ProductSales{
+			ProductID:   r.ProductID,
+			ProductName: r.ProductName,
+			TotalSales:  r.TotalSales,
+			Revenue:     models.MoneyFromFloat(r.Revenue),
+		}
+	}
+
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckProductSales(ctx, db, op.Limit, results)
+	})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+)
+
+// The base Get Product by ID loads models.Product, whose nullable description is a *string.
+// productNullString maps the same table with a sql.NullString instead. The COALESCE expression
+// has no model field to land in, so that variant selects it explicitly through Table.
+
+type productNullString struct {
+	ID          int
+	Name        string
+	Description sql.NullString
+	Price       models.Money
+	Stock       int
+	CreatedAt   time.Time
+}
+
+func (productNullString) TableName() string {
+	return "products"
+}
+
+type GetProductByIDNullString struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDNullString) Name() string {
+	return op.GetProductByID.Name() + " (sql.NullString)"
+}
+
+func (op *GetProductByIDNullString) Execute(int) error {
+	var product productNullString
+	return op.db.
+		First(&product, op.IDs.Next()).
+		Error
+}
+
+type GetProductByIDCoalesce struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDCoalesce) Name() string {
+	return op.GetProductByID.Name() + " (COALESCE)"
+}
+
+func (op *GetProductByIDCoalesce) Execute(int) error {
+	var product struct {
+		ID          int
+		Name        string
+		Description string
+		Price       models.Money
+		Stock       int
+		CreatedAt   time.Time
+	}
+
+	return op.db.
+		Table("products").
+		Select("id, name, COALESCE(description, '') AS description, price, stock, created_at").
+		Where("id = ?", op.IDs.Next()).
+		Take(&product).
+		Error
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// productWalk keeps the rows every page's Find loaded and their id sum for the check,
+// which reads the table through the *sql.DB underneath GORM.
+type productWalk struct {
+	rows  int
+	idSum int64
+	utils.PageTimes
+	GORM
+}
+
+func (w *productWalk) Check(int) error {
+	return w.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckProductWalk(ctx, db, w.rows, w.idSum)
+	})
+}
+
+func (w *productWalk) count(products []models.Product) {
+	for _, product := range products {
+		w.rows++
+		w.idSum += int64(product.ID)
+	}
+}
+
+type PaginateProductsOffset struct {
+	productWalk
+}
+
+func (op *PaginateProductsOffset) Name() string {
+	return "Paginate Products (Offset)"
+}
+
+func (op *PaginateProductsOffset) Execute(int) error {
+	op.rows, op.idSum = 0, 0
+	for page := 0; ; page++ {
+		start := time.Now()
+
+		var products []models.Product
+		if err := op.db.
+			Order("id").
+			Limit(utils.PageSize).
+			Offset(page * utils.PageSize).
+			Find(&products).
+			Error; err != nil {
+			return err
+		}
+		op.Add(page, time.Since(start))
+		op.count(products)
+
+		if len(products) < utils.PageSize {
+			return nil
+		}
+	}
+}
+
+type PaginateProductsKeyset struct {
+	productWalk
+}
+
+func (op *PaginateProductsKeyset) Name() string {
+	return "Paginate Products (Keyset)"
+}
+
+func (op *PaginateProductsKeyset) Execute(int) error {
+	op.rows, op.idSum = 0, 0
+	for page, last := 0, 0; ; page++ {
+		start := time.Now()
+
+		var products []models.Product
+		if err := op.db.
+			Where("id > ?", last).
+			Order("id").
+			Limit(utils.PageSize).
+			Find(&products).
+			Error; err != nil {
+			return err
+		}
+		op.Add(page, time.Since(start))
+		op.count(products)
+
+		if len(products) < utils.PageSize {
+			return nil
+		}
+		last = products[len(products)-1].ID
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/gorm"
+)
+
+// searchLimit is the number of products on the page a search returns.
+const searchLimit = 50
+
+func priceFrom(price models.Money) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("price >= ?", price)
+	}
+}
+
+func priceTo(price models.Money) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("price <= ?", price)
+	}
+}
+
+func inStock(db *gorm.DB) *gorm.DB {
+	return db.Where("stock > 0")
+}
+
+func nameContains(name string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("name ILIKE ?", "%"+name+"%")
+	}
+}
+
+func createdFrom(from time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("created_at >= ?", from)
+	}
+}
+
+func createdTo(to time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("created_at < ?", to)
+	}
+}
+
+// SearchProducts runs a product search with a random combination of filters in every iteration.
+// It reports how long building the query and running it took on their own.
+type SearchProducts struct {
+	Filters  *utils.FilterGenerator
+	filter   utils.ProductFilter
+	products []models.Product
+	utils.PhaseTimes
+	GORM
+}
+
+func (op *SearchProducts) Name() string {
+	return "Search Products (Dynamic Filters)"
+}
+
+func (op *SearchProducts) BeforeEach(int) error {
+	op.filter = op.Filters.Next()
+
+	return nil
+}
+
+// build chains the scopes of the applied filters and renders the query in a dry run session, so the
+// build phase includes the SQL GORM generates. They are applied right away instead of with Scopes,
+// which would defer them to the execution.
+func (op *SearchProducts) build() *gorm.Statement {
+	f := op.filter
+
+	var scopes []func(*gorm.DB) *gorm.DB
+	if f.MinPrice != nil {
+		scopes = append(scopes, priceFrom(*f.MinPrice))
+	}
+	if f.MaxPrice != nil {
+		scopes = append(scopes, priceTo(*f.MaxPrice))
+	}
+	if f.InStock {
+		scopes = append(scopes, inStock)
+	}
+	if f.Name != "" {
+		scopes = append(scopes, nameContains(f.Name))
+	}
+	if f.CreatedFrom != nil {
+		scopes = append(scopes, createdFrom(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		scopes = append(scopes, createdTo(*f.CreatedTo))
+	}
+
+	query := op.db.Model(&models.Product{})
+	for _, scope := range scopes {
+		query = scope(query)
+	}
+
+	return query.
+		Session(&gorm.Session{DryRun: true}).
+		Order("id").
+		Limit(searchLimit).
+		Find(&[]models.Product{}).
+		Statement
+}
+
+func (op *SearchProducts) Execute(int) error {
+	start := time.Now()
+	stmt := op.build()
+	op.Add("build", time.Since(start))
+
+	start = time.Now()
+	op.products = nil
+	err := op.db.Raw(stmt.SQL.String(), stmt.Vars...).Scan(&op.products).Error
+	op.Add("execute", time.Since(start))
+
+	return err
+}
+
+func (op *SearchProducts) Check(int) error {
+	ids := make([]int, len(op.products))
+	for i, p := range op.products {
+		ids[i] = p.ID
+	}
+
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckProductSearch(ctx, db, op.filter, searchLimit, ids)
+	})
+}
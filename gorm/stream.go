@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"gorm.io/gorm"
+)
+
+// orderProductStream is embedded by the GORM streaming operations, which differ only in how they fetch the lines.
+type orderProductStream struct {
+	reference.OrderProductStream
+	GORM
+}
+
+// Setup counts the lines in a subquery GORM builds from the limited id query.
+func (s *orderProductStream) Setup() error {
+	return s.db.
+		Table("(?) AS lines", s.db.Model(&models.OrderProduct{}).Select("id").Order("id").Limit(s.Limit)).
+		Select("COUNT(*), COALESCE(MAX(id), 0)").
+		Row().
+		Scan(&s.N, &s.LastID)
+}
+
+func (s *orderProductStream) Check(int) error {
+	return s.check(func(ctx context.Context, db *sql.DB) error {
+		return s.OrderProductStream.Check(ctx, db)
+	})
+}
+
+type StreamOrderProductsRows struct {
+	orderProductStream
+}
+
+func (op *StreamOrderProductsRows) Name() string {
+	return "Stream Order Products (Rows)"
+}
+
+// Execute scans the rows one at a time into the same line.
+func (op *StreamOrderProductsRows) Execute(int) error {
+	rows, err := op.db.
+		Model(&models.OrderProduct{}).
+		Where("id <= ?", op.LastID).
+		Order("id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.Reset()
+
+	var line models.OrderProduct
+	for rows.Next() {
+		if err := op.db.ScanRows(rows, &line); err != nil {
+			return err
+		}
+		op.Add(line.Price, line.Quantity)
+	}
+
+	return rows.Err()
+}
+
+type StreamOrderProductsFindInBatches struct {
+	BatchSize int
+	orderProductStream
+}
+
+func (op *StreamOrderProductsFindInBatches) Name() string {
+	return "Stream Order Products (FindInBatches)"
+}
+
+// Execute reads BatchSize lines per query, each batch after the last id of the previous one.
+func (op *StreamOrderProductsFindInBatches) Execute(int) error {
+	op.Reset()
+
+	var batch []models.OrderProduct
+	return op.db.
+		Where("id <= ?", op.LastID).
+		FindInBatches(&batch, op.BatchSize, func(*gorm.DB, int) error {
+			for _, line := range batch {
+				op.Add(line.Price, line.Quantity)
+			}
+
+			return nil
+		}).
+		Error
+}
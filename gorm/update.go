@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/gorm"
+)
+
+// repricing raises the price by 10% and adds Restock to the stock, evaluated by the database.
+var repricing = map[string]any{
+	"price": gorm.Expr("price * 1.1"),
+	"stock": gorm.Expr("stock + ?", reference.Restock),
+}
+
+// productUpdate is embedded by the GORM update operations, which apply repricing with UpdateColumns
+// and differ only in how they select the products.
+type productUpdate struct {
+	products map[int]models.Product
+	ids      []int
+	affected int
+	GORM
+}
+
+func (u *productUpdate) Setup() error {
+	var products []models.Product
+	if err := u.db.Select("id", "price", "stock").Find(&products).Error; err != nil {
+		return err
+	}
+
+	u.products = make(map[int]models.Product, len(products))
+	for _, p := range products {
+		u.products[p.ID] = p
+	}
+
+	return nil
+}
+
+// Rows returns the rows the last execution's statement reported as affected.
+func (u *productUpdate) Rows() int {
+	return u.affected
+}
+
+func (u *productUpdate) record(tx *gorm.DB) error {
+	u.affected = int(tx.RowsAffected)
+
+	return tx.Error
+}
+
+func (u *productUpdate) Check(int) error {
+	return u.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckProductsRepriced(ctx, db, u.products, u.ids)
+	})
+}
+
+func (u *productUpdate) AfterEach(int) error {
+	prices := make([]string, len(u.ids))
+	stocks := make([]int, len(u.ids))
+	for i, id := range u.ids {
+		prices[i] = u.products[id].Price.String()
+		stocks[i] = u.products[id].Stock
+	}
+
+	return u.db.
+		Exec(
+			`UPDATE products p SET price = s.price, stock = s.stock
+            FROM unnest(?::int[], ?::numeric[], ?::int[]) AS s(id, price, stock)
+            WHERE p.id = s.id`,
+			pq.Array(u.ids),
+			pq.Array(prices),
+			pq.Array(stocks),
+		).
+		Error
+}
+
+type UpdateProductByExpression struct {
+	IDs utils.Generator
+	productUpdate
+}
+
+func (op *UpdateProductByExpression) Name() string {
+	return "Update Product by Expression"
+}
+
+func (op *UpdateProductByExpression) BeforeEach(int) error {
+	op.ids = []int{op.IDs.Next()}
+
+	return nil
+}
+
+func (op *UpdateProductByExpression) Execute(int) error {
+	return op.record(op.db.
+		Model(&models.Product{ID: op.ids[0]}).
+		UpdateColumns(repricing),
+	)
+}
+
+type UpdateProductsByIDs struct {
+	IDs utils.Generator
+	productUpdate
+}
+
+func (op *UpdateProductsByIDs) Name() string {
+	return "Update Products by Expression (IN)"
+}
+
+func (op *UpdateProductsByIDs) BeforeEach(int) error {
+	var err error
+	op.ids, err = reference.NextUpdateBatch(op.IDs)
+
+	return err
+}
+
+func (op *UpdateProductsByIDs) Execute(int) error {
+	return op.record(op.db.
+		Model(&models.Product{}).
+		Where("id IN ?", op.ids).
+		UpdateColumns(repricing),
+	)
+}
+
+// UpdateProductsByPriceBand updates every product cheaper than CheapPrice, the same products in every
+// iteration since they are restored in between.
+type UpdateProductsByPriceBand struct {
+	productUpdate
+}
+
+func (op *UpdateProductsByPriceBand) Name() string {
+	return "Update Products by Expression (Price Band)"
+}
+
+func (op *UpdateProductsByPriceBand) Setup() error {
+	if err := op.productUpdate.Setup(); err != nil {
+		return err
+	}
+
+	for id, p := range op.products {
+		if p.Price < reference.CheapPrice {
+			op.ids = append(op.ids, id)
+		}
+	}
+
+	return nil
+}
+
+func (op *UpdateProductsByPriceBand) Execute(int) error {
+	return op.record(op.db.
+		Model(&models.Product{}).
+		Where("price < ?", reference.CheapPrice).
+		UpdateColumns(repricing),
+	)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertCustomerByEmail creates a customer with a clause.OnConflict on email whose DoUpdates assigns
+// only the name, which GORM renders as ON CONFLICT ("email") DO UPDATE SET "name"="excluded"."name"
+// and still reads the id back with RETURNING. ConflictRatio is the share of iterations that reuse
+// an existing email. Teardown restores the names and deletes the inserted customers.
+type UpsertCustomerByEmail struct {
+	CustomerIDs   utils.Generator
+	ConflictRatio float64
+	customers     map[int]models.Customer
+	renamed       map[int]bool
+	email         string
+	name          string
+	lastID        int
+	GORM
+}
+
+func (op *UpsertCustomerByEmail) Name() string {
+	return "Upsert Customer by Email"
+}
+
+func (op *UpsertCustomerByEmail) Setup() error {
+	var customers []models.Customer
+	if err := op.db.Select("id", "name", "email").Find(&customers).Error; err != nil {
+		return err
+	}
+
+	op.customers = make(map[int]models.Customer, len(customers))
+	op.renamed = make(map[int]bool)
+	for _, c := range customers {
+		op.customers[c.ID] = c
+		op.lastID = max(op.lastID, c.ID)
+	}
+
+	return nil
+}
+
+func (op *UpsertCustomerByEmail) BeforeEach(iteration int) error {
+	op.name = fmt.Sprintf("Customer_%d", iteration)
+	op.email = fmt.Sprintf("customer_%d@upsert.test", iteration)
+
+	if float64(iteration%100) < op.ConflictRatio*100 {
+		id := op.CustomerIDs.Next()
+		op.email = op.customers[id].Email
+		op.renamed[id] = true
+	}
+
+	return nil
+}
+
+func (op *UpsertCustomerByEmail) Execute(int) error {
+	return op.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "email"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+		}).
+		Create(&models.Customer{
+			Name:  op.name,
+			Email: op.email,
+		}).
+		Error
+}
+
+func (op *UpsertCustomerByEmail) Check(int) error {
+	return op.check(func(ctx context.Context, db *sql.DB) error {
+		return reference.CheckCustomerByEmail(ctx, db, op.email, op.name)
+	})
+}
+
+func (op *UpsertCustomerByEmail) Teardown() error {
+	var errs []error
+	for id := range op.renamed {
+		errs = append(errs, op.db.
+			Model(&models.Customer{ID: id}).
+			Update("name", op.customers[id].Name).
+			Error,
+		)
+	}
+
+	errs = append(errs, op.db.
+		Where("id > ?", op.lastID).
+		Delete(&models.Customer{}).
+		Error,
+	)
+
+	return errors.Join(errs...)
+}
+
+type UpsertCustomerByEmailHighConflict struct {
+	UpsertCustomerByEmail
+}
+
+func (op *UpsertCustomerByEmailHighConflict) Name() string {
+	return op.UpsertCustomerByEmail.Name() + " (High Conflict)"
+}
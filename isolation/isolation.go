@@ -0,0 +1,190 @@
+package isolation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+const (
+	snapshotSchema = "snapshot"
+	templateSuffix = "_template"
+	maintenanceDB  = "postgres"
+)
+
+// tables are listed in foreign key order, so they can be refilled one after another.
+var tables = []string{"customers", "products", "orders", "order_products", "dataset_metadata"}
+
+// Apply brings the database behind dsn back to its baseline state using the given strategy.
+// It must run before the implementation opens its own connections: the template strategy
+// drops and recreates the whole database.
+func Apply(ctx context.Context, strategy, dsn string) error {
+	switch strategy {
+	case "none":
+		return nil
+	case "reseed":
+		return withDB(ctx, dsn, reseed)
+	case "snapshot":
+		return withDB(ctx, dsn, restoreSnapshot)
+	case "template":
+		return recreateFromTemplate(ctx, dsn)
+	default:
+		return fmt.Errorf("unknown isolation strategy \"%s\"", strategy)
+	}
+}
+
+// Discard drops the snapshot tables and the template database,
+// so that the next run captures the current dataset as its baseline.
+func Discard(ctx context.Context, dsn string) error {
+	if err := withDB(ctx, dsn, func(ctx context.Context, db *sql.DB) error {
+		_, err := db.ExecContext(ctx, "DROP SCHEMA IF EXISTS "+snapshotSchema+" CASCADE")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	name := utils.DSNValue(dsn, "dbname")
+	if name == "" {
+		return nil
+	}
+
+	return withDB(ctx, utils.WithDSNValue(dsn, "dbname", maintenanceDB), func(ctx context.Context, db *sql.DB) error {
+		_, err := db.ExecContext(ctx, "DROP DATABASE IF EXISTS "+pq.QuoteIdentifier(name+templateSuffix))
+		return err
+	})
+}
+
+func reseed(ctx context.Context, db *sql.DB) error {
+	metadata, err := dataset.Describe(ctx, db)
+	if err != nil {
+		return err
+	}
+	if metadata.Scale == 0 {
+		return errors.New("database has no dataset metadata, load it with the seed command first")
+	}
+
+	_, err = dataset.Load(ctx, db, dataset.Options{
+		Scale: metadata.Scale,
+		Seed:  metadata.Seed,
+	})
+
+	return err
+}
+
+// restoreSnapshot copies the tables into the snapshot schema on the first run
+// and restores them from it on every later one.
+func restoreSnapshot(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.
+		QueryRowContext(
+			ctx,
+			"SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)",
+			snapshotSchema,
+		).
+		Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := tx.ExecContext(ctx, "CREATE SCHEMA "+snapshotSchema); err != nil {
+			return err
+		}
+
+		for _, table := range tables {
+			if _, err := tx.ExecContext(
+				ctx,
+				fmt.Sprintf("CREATE TABLE %s.%s AS TABLE %s", snapshotSchema, table, table),
+			); err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY",
+	); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if _, err := tx.ExecContext(
+			ctx,
+			fmt.Sprintf("INSERT INTO %s SELECT * FROM %s.%s", table, snapshotSchema, table),
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := dataset.SyncSequences(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// recreateFromTemplate replaces the database with a fresh copy of its template database.
+// The template is created from the current database the first time it is needed.
+func recreateFromTemplate(ctx context.Context, dsn string) error {
+	name := utils.DSNValue(dsn, "dbname")
+	if name == "" {
+		return errors.New("template isolation requires dbname in the DSN")
+	}
+	template := name + templateSuffix
+
+	return withDB(ctx, utils.WithDSNValue(dsn, "dbname", maintenanceDB), func(ctx context.Context, db *sql.DB) error {
+		var exists bool
+		if err := db.
+			QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)", template).
+			Scan(&exists); err != nil {
+			return err
+		}
+
+		if !exists {
+			if _, err := db.ExecContext(
+				ctx,
+				fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", pq.QuoteIdentifier(template), pq.QuoteIdentifier(name)),
+			); err != nil {
+				return err
+			}
+		}
+
+		if _, err := db.ExecContext(
+			ctx,
+			fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", pq.QuoteIdentifier(name)),
+		); err != nil {
+			return err
+		}
+
+		_, err := db.ExecContext(
+			ctx,
+			fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", pq.QuoteIdentifier(name), pq.QuoteIdentifier(template)),
+		)
+
+		return err
+	})
+}
+
+func withDB(ctx context.Context, dsn string, fn func(context.Context, *sql.DB) error) error {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(ctx, db)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	_ "github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/migrations"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+func version(arg string) int64 {
+	v, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		log.Fatalf("invalid version \"%s\": %v", arg, err)
+	}
+
+	return v
+}
+
+func main() {
+	dsn := flag.String("dsn", utils.DefaultDSN, "PostgreSQL connection string")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate [-dsn dsn] up | down | to <version> | baseline <version> | status")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	db, err := sql.Open("postgres", *dsn)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+
+	ctx := context.Background()
+
+	switch flag.Arg(0) {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		err = migrator.Down(ctx)
+	case "to":
+		err = migrator.To(ctx, version(flag.Arg(1)))
+	case "baseline":
+		err = migrator.Baseline(ctx, version(flag.Arg(1)))
+	case "status":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("failed to migrate: %v", err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatalf("failed to get migration status: %v", err)
+	}
+
+	fmt.Printf("%-16s %-30s %-10s %-30s\n", "Version", "Name", "State", "Applied At")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		if status.Modified {
+			state = "modified"
+		}
+
+		fmt.Printf("%-16d %-30s %-10s %-30s\n", status.Version, status.Name, state, appliedAt)
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS dataset_metadata;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS dataset_metadata (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    scale DOUBLE PRECISION NOT NULL,
+    seed BIGINT NOT NULL,
+    customers INTEGER NOT NULL,
+    products INTEGER NOT NULL,
+    orders INTEGER NOT NULL,
+    order_products INTEGER NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
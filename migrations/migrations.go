@@ -0,0 +1,460 @@
+package migrations
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// table is named apart from golang-migrate's schema_migrations, which has other columns and may live
+// in the same database.
+const table = "public.benchmark_schema_migrations"
+
+// lockKey identifies the session advisory lock that serializes migrators running against one database.
+const lockKey int64 = 0x62656e63686d6b // "benchmk"
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool
+}
+
+// Load parses the embedded <version>_<name>.up.sql and .down.sql files, ordered by version.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name \"%s\"", entry.Name())
+		}
+
+		prefix, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name \"%s\"", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in \"%s\": %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+
+		if direction == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d has no up file", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Latest returns the version the schema is expected to be at.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func() error {
+		return m.to(ctx, m.Latest())
+	})
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.locked(ctx, func() error {
+		current, err := m.Current(ctx)
+		if err != nil {
+			return err
+		}
+		if current == 0 {
+			return nil
+		}
+
+		target := int64(0)
+		for _, migration := range m.migrations {
+			if migration.Version < current {
+				target = migration.Version
+			}
+		}
+
+		return m.to(ctx, target)
+	})
+}
+
+// To applies or reverts migrations until the schema is at the given version, 0 reverting all of them.
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	return m.locked(ctx, func() error {
+		return m.to(ctx, version)
+	})
+}
+
+// Baseline records every migration up to the given version as applied without running it,
+// for databases whose tables were created before this engine tracked them.
+// It refuses databases that already have applied migrations.
+func (m *Migrator) Baseline(ctx context.Context, version int64) error {
+	if err := m.known(version); err != nil {
+		return err
+	}
+
+	return m.locked(ctx, func() error {
+		if err := m.ensureTable(ctx); err != nil {
+			return err
+		}
+
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 0 {
+			return fmt.Errorf("cannot baseline a database with %d applied migrations", len(applied))
+		}
+
+		tx, err := m.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if err := record(ctx, tx, migration); err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+}
+
+func (m *Migrator) known(version int64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool {
+		return migration.Version == version
+	}) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return nil
+}
+
+// locked runs fn while holding the migration advisory lock. The lock belongs to one session,
+// so it is taken on a dedicated connection that is kept until fn returns.
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+
+	err = fn()
+
+	// The unlock must run even when ctx was cancelled, or the pooled connection keeps the lock.
+	if _, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockKey); unlockErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to unlock migrations: %w", unlockErr))
+	}
+
+	return err
+}
+
+func (m *Migrator) to(ctx context.Context, version int64) error {
+	if err := m.known(version); err != nil {
+		return err
+	}
+
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	if err := m.verify(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, migration := range slices.Backward(m.migrations) {
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.revert(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Current returns the highest applied version, or 0 when nothing was applied.
+func (m *Migrator) Current(ctx context.Context) (int64, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	for v := range applied {
+		version = max(version, v)
+	}
+
+	return version, nil
+}
+
+// Status reports every known migration together with its state in the database.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.appliedAt
+			status.Modified = row.checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Check fails unless the schema is at the latest version and no applied migration was edited since.
+// Every benchmark bootstrap runs it before measuring anything.
+func Check(ctx context.Context, db *sql.DB) error {
+	m, err := New(db)
+	if err != nil {
+		return err
+	}
+
+	if err := m.verify(ctx); err != nil {
+		return err
+	}
+
+	current, err := m.Current(ctx)
+	if err != nil {
+		return err
+	}
+
+	if current != m.Latest() {
+		return fmt.Errorf("schema is at version %d, expected %d: run the migrate command", current, m.Latest())
+	}
+
+	return nil
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// ensureTable creates the version table in public, where it is found whatever schema comes first
+// in the search_path of the DSN.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(
+		ctx,
+		`CREATE TABLE IF NOT EXISTS `+table+` (
+            version BIGINT PRIMARY KEY,
+            name TEXT NOT NULL,
+            checksum TEXT NOT NULL,
+            applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+        )`,
+	)
+
+	return err
+}
+
+// applied reads the version table without creating it, so checking a database never writes to it.
+// A database without the table has nothing applied.
+func (m *Migrator) applied(ctx context.Context) (map[int64]appliedMigration, error) {
+	var exists bool
+	if err := m.db.
+		QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", table).
+		Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return map[int64]appliedMigration{}, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, checksum, applied_at FROM "+table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var version int64
+		var row appliedMigration
+		if err := rows.Scan(&version, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = row
+	}
+
+	return applied, rows.Err()
+}
+
+// verify rejects databases with applied migrations that were edited or are unknown to this build.
+func (m *Migrator) verify(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for version, row := range applied {
+		i := slices.IndexFunc(m.migrations, func(migration Migration) bool {
+			return migration.Version == version
+		})
+
+		switch {
+		case i == -1:
+			errs = append(errs, fmt.Errorf("applied migration %d is unknown", version))
+		case m.migrations[i].Checksum != row.checksum:
+			errs = append(errs, fmt.Errorf("applied migration %d_%s was modified", version, m.migrations[i].Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	return m.inTx(ctx, migration, migration.Up, func(tx *sql.Tx) error {
+		return record(ctx, tx, migration)
+	})
+}
+
+func record(ctx context.Context, tx *sql.Tx, migration Migration) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO "+table+" (version, name, checksum) VALUES ($1, $2, $3)",
+		migration.Version,
+		migration.Name,
+		migration.Checksum,
+	)
+
+	return err
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+	}
+
+	return m.inTx(ctx, migration, migration.Down, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE version = $1", migration.Version)
+
+		return err
+	})
+}
+
+// inTx runs the migration script and its bookkeeping in one transaction,
+// so a failing script leaves neither schema changes nor a version row behind.
+func (m *Migrator) inTx(ctx context.Context, migration Migration, script string, record func(*sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
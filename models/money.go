@@ -0,0 +1,112 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount of cents. It maps onto the NUMERIC(10, 2) price and total columns
+// without the rounding drift of float64.
+type Money int64
+
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * 100))
+}
+
+// ParseMoney parses a decimal string such as "1299.99", rounding any digits after the cents half away from zero.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(s, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimLeft(s, "+-"), ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid money value \"%s\"", s)
+	}
+
+	var units int64
+	if whole != "" {
+		var err error
+		if units, err = strconv.ParseInt(whole, 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid money value \"%s\": %w", s, err)
+		}
+	}
+
+	var cents int64
+	for i, c := range fraction {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid money value \"%s\"", s)
+		}
+
+		digit := int64(c - '0')
+		switch {
+		case i == 0:
+			cents += digit * 10
+		case i == 1:
+			cents += digit
+		case i == 2 && digit >= 5:
+			cents++
+		}
+	}
+
+	m := Money(units*100 + cents)
+	if negative {
+		m = -m
+	}
+
+	return m, nil
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Scan implements sql.Scanner. NUMERIC columns arrive as text and are parsed exactly,
+// float columns (e.g. in the schema Ent creates itself) are rounded to the nearest cent.
+// NULL is rejected, the money columns are all NOT NULL.
+func (m *Money) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case []byte:
+		*m, err = ParseMoney(string(v))
+	case string:
+		*m, err = ParseMoney(v)
+	case int64:
+		*m = Money(v * 100)
+	case float64:
+		*m = MoneyFromFloat(v)
+	case nil:
+		err = errors.New("cannot scan NULL into Money")
+	default:
+		err = fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	return err
+}
+
+// Value implements driver.Valuer and sends the amount as an exact decimal literal.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// GormDataType makes GORM create Money fields as NUMERIC(10, 2) columns.
+func (Money) GormDataType() string {
+	return "numeric(10,2)"
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// Only the third fractional digit rounds; later digits are ignored, and the sign
+// applies after rounding so negative amounts round away from zero too.
+func TestParseMoneyRounding(t *testing.T) {
+	for in, want := range map[string]Money{
+		"1.004":  100,
+		"1.005":  101,
+		"1.0049": 100,
+		"9.995":  1000,
+		"-1.005": -101,
+		"-0.004": 0,
+		"0.5":    50,
+		".25":    25,
+		"12.":    1200,
+		" +3.1 ": 310,
+	} {
+		if got, err := ParseMoney(in); err != nil || got != want {
+			t.Errorf("ParseMoney(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+}
+
+func TestParseMoneyRejects(t *testing.T) {
+	for _, in := range []string{"", "-", ".", "abc", "1.2x", "1e3", "1,50"} {
+		if got, err := ParseMoney(in); err == nil {
+			t.Errorf("ParseMoney(%q) = %d, want an error", in, got)
+		}
+	}
+}
+
+// Sums that drift as float64 stay exact as Money.
+func TestMoneyAvoidsFloatDrift(t *testing.T) {
+	a, b := 0.1, 0.2
+	if a+b == 0.3 {
+		t.Fatal("expected 0.1 + 0.2 to drift as float64")
+	}
+	if got := MoneyFromFloat(a) + MoneyFromFloat(b); got != MoneyFromFloat(0.3) {
+		t.Errorf("0.10 + 0.20 = %s, want 0.30", got)
+	}
+
+	price, _ := ParseMoney("19.99")
+	if got := price.Mul(3); got.String() != "59.97" {
+		t.Errorf("19.99 * 3 = %s, want 59.97", got)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	for m, want := range map[Money]string{
+		0:       "0.00",
+		5:       "0.05",
+		-5:      "-0.05",
+		-100:    "-1.00",
+		129999:  "1299.99",
+		1 << 40: "10995116277.76",
+	} {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(m), got, want)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	scan := func(src any) (Money, error) {
+		var m Money
+		err := m.Scan(src)
+		return m, err
+	}
+
+	// NUMERIC arrives as text from pgx and lib/pq alike.
+	if m, err := scan([]byte("1299.99")); err != nil || m != 129999 {
+		t.Errorf("Scan([]byte) = %d, %v", m, err)
+	}
+	if m, err := scan("-0.50"); err != nil || m != -50 {
+		t.Errorf("Scan(string) = %d, %v", m, err)
+	}
+	// Integers are whole units, floats are rounded to the nearest cent.
+	if m, err := scan(int64(12)); err != nil || m != 1200 {
+		t.Errorf("Scan(int64) = %d, %v", m, err)
+	}
+	if m, err := scan(19.989999999); err != nil || m != 1999 {
+		t.Errorf("Scan(float64) = %d, %v", m, err)
+	}
+
+	if _, err := scan(nil); err == nil {
+		t.Error("Scan(nil) succeeded, the money columns are NOT NULL")
+	}
+	if _, err := scan(true); err == nil {
+		t.Error("Scan(bool) succeeded")
+	}
+}
+
+// Every amount survives the trip through Value and back through Scan,
+// which is what a write followed by a read does.
+func TestMoneyValueRoundTrip(t *testing.T) {
+	for _, m := range []Money{0, 1, -1, 99, -99, 100, 129999, -129999, math.MaxInt32} {
+		v, err := m.Value()
+		if err != nil {
+			t.Fatalf("Money(%d).Value() failed: %v", int64(m), err)
+		}
+
+		var back Money
+		if err := back.Scan(v); err != nil || back != m {
+			t.Errorf("Money(%d) came back as %d, %v via %v", int64(m), int64(back), err, v)
+		}
+	}
+}
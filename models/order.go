@@ -6,7 +6,7 @@ type Order struct {
 	ID         int
 	CustomerID int
 	Date       time.Time
-	Total      float64
+	Total      Money
 	Products   []OrderProduct
 	CreatedAt  time.Time
 }
@@ -6,5 +6,5 @@ type OrderProduct struct {
 	ProductID int
 	Product   Product
 	Quantity  int
-	Price     float64
+	Price     Money
 }
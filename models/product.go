@@ -5,8 +5,8 @@ import "time"
 type Product struct {
 	ID          int
 	Name        string
-	Description string
-	Price       float64
+	Description *string
+	Price       Money
 	Stock       int
 	CreatedAt   time.Time
 }
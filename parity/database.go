@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// introspect reads the given tables of the current schema from the PostgreSQL catalogs.
+func introspect(ctx context.Context, db *sql.DB, tables []string) (Schema, error) {
+	schema := make(Schema)
+
+	columns, err := db.QueryContext(
+		ctx,
+		`SELECT table_name, column_name, udt_name, character_maximum_length, numeric_precision, numeric_scale, is_nullable = 'YES'
+        FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = ANY($1)`,
+		pq.Array(tables),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer columns.Close()
+
+	for columns.Next() {
+		var table, column, udt string
+		var length, precision, scale sql.NullInt64
+		var nullable bool
+		if err := columns.Scan(&table, &column, &udt, &length, &precision, &scale, &nullable); err != nil {
+			return nil, err
+		}
+
+		t := normalizeType(udt)
+		switch {
+		case t == "varchar" && length.Valid:
+			t = fmt.Sprintf("varchar(%d)", length.Int64)
+		case t == "numeric" && precision.Valid:
+			t = fmt.Sprintf("numeric(%d,%d)", precision.Int64, scale.Int64)
+		}
+
+		if _, ok := schema[table]; !ok {
+			schema[table] = newTable()
+		}
+		schema[table].Columns[column] = Column{Type: t, Nullable: nullable}
+	}
+	if err := columns.Err(); err != nil {
+		return nil, err
+	}
+
+	indexes, err := db.QueryContext(
+		ctx,
+		`SELECT t.relname, ix.indisprimary, ix.indisunique, array_agg(a.attname ORDER BY k.ord)
+        FROM pg_index ix
+        JOIN pg_class t ON t.oid = ix.indrelid
+        JOIN pg_namespace n ON n.oid = t.relnamespace
+        CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord)
+        JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+        WHERE n.nspname = current_schema() AND t.relname = ANY($1)
+        GROUP BY t.relname, ix.indexrelid, ix.indisprimary, ix.indisunique`,
+		pq.Array(tables),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer indexes.Close()
+
+	for indexes.Next() {
+		var table string
+		var primary, unique bool
+		var columns []string
+		if err := indexes.Scan(&table, &primary, &unique, pq.Array(&columns)); err != nil {
+			return nil, err
+		}
+
+		kind := "INDEX"
+		switch {
+		case primary:
+			kind = "PRIMARY KEY"
+		case unique:
+			kind = "UNIQUE"
+		}
+
+		if t, ok := schema[table]; ok {
+			t.Indexes = append(t.Indexes, index(kind, columns...))
+		}
+	}
+	if err := indexes.Err(); err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := db.QueryContext(
+		ctx,
+		`SELECT kcu.table_name, kcu.column_name, ccu.table_name, ccu.column_name, rc.delete_rule
+        FROM information_schema.referential_constraints rc
+        JOIN information_schema.key_column_usage kcu
+            ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name
+        JOIN information_schema.constraint_column_usage ccu
+            ON ccu.constraint_schema = rc.constraint_schema AND ccu.constraint_name = rc.constraint_name
+        WHERE rc.constraint_schema = current_schema() AND kcu.table_name = ANY($1)`,
+		pq.Array(tables),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer foreignKeys.Close()
+
+	for foreignKeys.Next() {
+		var table, column, refTable, refColumn, onDelete string
+		if err := foreignKeys.Scan(&table, &column, &refTable, &refColumn, &onDelete); err != nil {
+			return nil, err
+		}
+
+		if t, ok := schema[table]; ok {
+			t.ForeignKeys = append(t.ForeignKeys, foreignKey(column, refTable, refColumn, onDelete))
+		}
+	}
+
+	return schema, foreignKeys.Err()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+
+	"entgo.io/ent/dialect"
+	entschema "entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/schema/field"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/ent/generated/migrate"
+)
+
+// maxCharSize is the length above which Ent stores strings as text on PostgreSQL.
+const maxCharSize = 10 << 20
+
+// entSchema converts the tables Ent would migrate to, using the types Ent picks for PostgreSQL.
+func entSchema() Schema {
+	schema := make(Schema)
+
+	for _, table := range migrate.Tables {
+		t := newTable()
+
+		for _, column := range table.Columns {
+			t.Columns[column.Name] = Column{
+				Type:     entType(column),
+				Nullable: column.Nullable,
+			}
+
+			if column.Unique {
+				t.Indexes = append(t.Indexes, index("UNIQUE", column.Name))
+			}
+		}
+
+		primaryKey := make([]string, len(table.PrimaryKey))
+		for i, column := range table.PrimaryKey {
+			primaryKey[i] = column.Name
+		}
+		t.Indexes = append(t.Indexes, index("PRIMARY KEY", primaryKey...))
+
+		for _, idx := range table.Indexes {
+			columns := make([]string, len(idx.Columns))
+			for i, column := range idx.Columns {
+				columns[i] = column.Name
+			}
+
+			kind := "INDEX"
+			if idx.Unique {
+				kind = "UNIQUE"
+			}
+			t.Indexes = append(t.Indexes, index(kind, columns...))
+		}
+
+		for _, fk := range table.ForeignKeys {
+			t.ForeignKeys = append(t.ForeignKeys, foreignKey(
+				fk.Columns[0].Name,
+				fk.RefTable.Name,
+				fk.RefColumns[0].Name,
+				string(fk.OnDelete),
+			))
+		}
+
+		schema[table.Name] = t
+	}
+
+	return schema
+}
+
+func entType(column *entschema.Column) string {
+	if t := column.SchemaType[dialect.Postgres]; t != "" {
+		return normalizeType(t)
+	}
+
+	switch column.Type {
+	case field.TypeBool:
+		return "boolean"
+	case field.TypeUint8, field.TypeInt8, field.TypeInt16:
+		return "smallint"
+	case field.TypeUint16, field.TypeInt32:
+		return "integer"
+	case field.TypeUint32, field.TypeInt, field.TypeUint, field.TypeInt64, field.TypeUint64:
+		return "bigint"
+	case field.TypeFloat32:
+		return "real"
+	case field.TypeFloat64:
+		return "double precision"
+	case field.TypeBytes:
+		return "bytea"
+	case field.TypeUUID:
+		return "uuid"
+	case field.TypeJSON:
+		return "jsonb"
+	case field.TypeTime:
+		return "timestamptz"
+	case field.TypeString, field.TypeEnum:
+		switch {
+		case column.Size > maxCharSize:
+			return "text"
+		case column.Size > 0:
+			return fmt.Sprintf("varchar(%d)", column.Size)
+		default:
+			return "varchar"
+		}
+	default:
+		return column.Type.String()
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm/schema"
+)
+
+// gormSchema parses the models the way GORM's AutoMigrate does and uses
+// the PostgreSQL dialector to pick the column types.
+func gormSchema() (Schema, error) {
+	cache := &sync.Map{}
+	dialector := postgres.Dialector{}
+	result := make(Schema)
+
+	var parsed []*schema.Schema
+	for _, model := range []any{&models.Customer{}, &models.Product{}, &models.Order{}, &models.OrderProduct{}} {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, s)
+	}
+
+	for _, s := range parsed {
+		t := newTable()
+
+		for _, f := range s.Fields {
+			if f.DBName == "" {
+				continue
+			}
+
+			t.Columns[f.DBName] = Column{
+				Type:     normalizeType(dialector.DataTypeOf(f)),
+				Nullable: !f.NotNull && !f.PrimaryKey,
+			}
+
+			if f.Unique {
+				t.Indexes = append(t.Indexes, index("UNIQUE", f.DBName))
+			}
+		}
+
+		primaryKey := make([]string, len(s.PrimaryFields))
+		for i, f := range s.PrimaryFields {
+			primaryKey[i] = f.DBName
+		}
+		t.Indexes = append(t.Indexes, index("PRIMARY KEY", primaryKey...))
+
+		for _, idx := range s.ParseIndexes() {
+			columns := make([]string, len(idx.Fields))
+			for i, f := range idx.Fields {
+				columns[i] = f.DBName
+			}
+
+			kind := "INDEX"
+			if strings.EqualFold(idx.Class, "UNIQUE") {
+				kind = "UNIQUE"
+			}
+			t.Indexes = append(t.Indexes, index(kind, columns...))
+		}
+
+		result[s.Table] = t
+	}
+
+	// Relationships are declared on either side, but the constraint always lives on the table holding the foreign key.
+	seen := make(map[string]struct{})
+	for _, s := range parsed {
+		for _, rel := range s.Relationships.Relations {
+			constraint := rel.ParseConstraint()
+			if constraint == nil {
+				continue
+			}
+
+			fk := foreignKey(
+				constraint.ForeignKeys[0].DBName,
+				constraint.ReferenceSchema.Table,
+				constraint.References[0].DBName,
+				constraint.OnDelete,
+			)
+			key := constraint.Schema.Table + " " + fk
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			if t, ok := result[constraint.Schema.Table]; ok {
+				t.ForeignKeys = append(t.ForeignKeys, fk)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	_ "github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// Schema is the normalized view of one schema source, keyed by table name.
+type Schema map[string]*Table
+
+type Table struct {
+	Columns     map[string]Column
+	Indexes     []string
+	ForeignKeys []string
+}
+
+type Column struct {
+	Type     string
+	Nullable bool
+}
+
+type source struct {
+	name   string
+	schema Schema
+}
+
+func newTable() *Table {
+	return &Table{Columns: make(map[string]Column)}
+}
+
+func index(kind string, columns ...string) string {
+	return fmt.Sprintf("%s (%s)", kind, strings.Join(columns, ", "))
+}
+
+func foreignKey(column, refTable, refColumn, onDelete string) string {
+	if onDelete == "" {
+		onDelete = "NO ACTION"
+	}
+
+	return fmt.Sprintf("%s -> %s(%s) ON DELETE %s", column, refTable, refColumn, strings.ToUpper(onDelete))
+}
+
+var (
+	spaces      = regexp.MustCompile(`\s+`)
+	typeAliases = map[string]string{
+		"int":                         "integer",
+		"int4":                        "integer",
+		"serial":                      "integer",
+		"serial4":                     "integer",
+		"int8":                        "bigint",
+		"bigserial":                   "bigint",
+		"serial8":                     "bigint",
+		"int2":                        "smallint",
+		"smallserial":                 "smallint",
+		"float8":                      "double precision",
+		"float4":                      "real",
+		"decimal":                     "numeric",
+		"bool":                        "boolean",
+		"character varying":           "varchar",
+		"timestamp with time zone":    "timestamptz",
+		"timestamp without time zone": "timestamp",
+	}
+)
+
+// normalizeType maps the spellings used by PostgreSQL, Ent and GORM to one canonical form,
+// e.g. "character varying(100)" and "VARCHAR(100)" both become "varchar(100)".
+func normalizeType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(spaces.ReplaceAllString(t, " ")))
+
+	base, args, _ := strings.Cut(t, "(")
+	base = strings.TrimSpace(base)
+	if alias, ok := typeAliases[base]; ok {
+		base = alias
+	}
+	if args == "" {
+		return base
+	}
+
+	return base + "(" + strings.ReplaceAll(args, " ", "")
+}
+
+type difference struct {
+	subject string
+	values  []string
+}
+
+// compare lists every column, index and foreign key on which the sources do not agree.
+func compare(sources []source) []difference {
+	var differences []difference
+
+	tables := make(map[string]struct{})
+	for _, s := range sources {
+		for name := range s.schema {
+			tables[name] = struct{}{}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(tables)) {
+		columns := make(map[string]struct{})
+		for _, s := range sources {
+			if t, ok := s.schema[name]; ok {
+				for column := range t.Columns {
+					columns[column] = struct{}{}
+				}
+			}
+		}
+
+		for _, column := range slices.Sorted(maps.Keys(columns)) {
+			types := make([]string, len(sources))
+			nullability := make([]string, len(sources))
+			for i, s := range sources {
+				t, ok := s.schema[name]
+				if !ok {
+					types[i], nullability[i] = "missing table", "missing table"
+					continue
+				}
+
+				c, ok := t.Columns[column]
+				if !ok {
+					types[i], nullability[i] = "missing", "missing"
+					continue
+				}
+
+				types[i] = c.Type
+				nullability[i] = "NOT NULL"
+				if c.Nullable {
+					nullability[i] = "NULL"
+				}
+			}
+
+			if !allEqual(types) {
+				differences = append(differences, difference{subject: name + "." + column + " type", values: types})
+			}
+			if !allEqual(nullability) {
+				differences = append(differences, difference{subject: name + "." + column + " nullability", values: nullability})
+			}
+		}
+
+		differences = append(differences, compareSets(name, "index", sources, func(t *Table) []string { return t.Indexes })...)
+		differences = append(differences, compareSets(name, "foreign key", sources, func(t *Table) []string { return t.ForeignKeys })...)
+	}
+
+	return differences
+}
+
+func compareSets(table, kind string, sources []source, items func(*Table) []string) []difference {
+	var differences []difference
+
+	all := make(map[string]struct{})
+	for _, s := range sources {
+		if t, ok := s.schema[table]; ok {
+			for _, item := range items(t) {
+				all[item] = struct{}{}
+			}
+		}
+	}
+
+	for _, item := range slices.Sorted(maps.Keys(all)) {
+		values := make([]string, len(sources))
+		for i, s := range sources {
+			values[i] = "missing"
+			if t, ok := s.schema[table]; ok && slices.Contains(items(t), item) {
+				values[i] = "present"
+			}
+		}
+
+		if !allEqual(values) {
+			differences = append(differences, difference{subject: fmt.Sprintf("%s %s %s", table, kind, item), values: values})
+		}
+	}
+
+	return differences
+}
+
+func allEqual(values []string) bool {
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func main() {
+	dsn := flag.String("dsn", utils.DefaultDSN, "PostgreSQL connection string")
+	flag.Parse()
+
+	db, err := sql.Open("postgres", *dsn)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+	defer db.Close()
+
+	entSchema := entSchema()
+
+	gormSchema, err := gormSchema()
+	if err != nil {
+		log.Fatalf("failed to parse GORM models: %v", err)
+	}
+
+	tables := slices.Sorted(maps.Keys(entSchema))
+	for name := range gormSchema {
+		if !slices.Contains(tables, name) {
+			tables = append(tables, name)
+		}
+	}
+
+	databaseSchema, err := introspect(context.Background(), db, tables)
+	if err != nil {
+		log.Fatalf("failed to introspect database: %v", err)
+	}
+
+	sources := []source{
+		{name: "database", schema: databaseSchema},
+		{name: "ent", schema: entSchema},
+		{name: "gorm", schema: gormSchema},
+	}
+
+	differences := compare(sources)
+	if len(differences) == 0 {
+		fmt.Println("database, ent and gorm schemas match")
+		return
+	}
+
+	fmt.Printf("%-60s", "Difference")
+	for _, s := range sources {
+		fmt.Printf(" %-20s", s.name)
+	}
+	fmt.Println()
+
+	for _, d := range differences {
+		fmt.Printf("%-60s", d.subject)
+		for _, v := range d.values {
+			fmt.Printf(" %-20s", v)
+		}
+		fmt.Println()
+	}
+
+	os.Exit(1)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// The three sources spell the same types differently: the catalog reports
+// "character varying(100)", Ent emits "varchar(100)", GORM tags say "VARCHAR(100)".
+func TestNormalizeTypeSpellings(t *testing.T) {
+	for canonical, spellings := range map[string][]string{
+		"varchar(100)":  {"character varying(100)", "VARCHAR(100)", "varchar ( 100 )"},
+		"numeric(10,2)": {"numeric(10,2)", "NUMERIC(10, 2)", "decimal(10, 2)"},
+		"integer":       {"int", "int4", "serial", "SERIAL4"},
+		"bigint":        {"int8", "bigserial", "serial8"},
+		"timestamptz":   {"timestamp with time zone", "timestamp  with\ttime zone"},
+		"timestamp":     {"timestamp without time zone"},
+		// Unknown types pass through, only lowercased and trimmed.
+		"jsonb": {"JSONB", "  jsonb "},
+	} {
+		for _, s := range spellings {
+			if got := normalizeType(s); got != canonical {
+				t.Errorf("normalizeType(%q) = %q, want %q", s, got, canonical)
+			}
+		}
+	}
+}
+
+// Length and precision are part of the type, so only the spelling is normalized.
+func TestNormalizeTypeKeepsArguments(t *testing.T) {
+	if normalizeType("varchar(100)") == normalizeType("varchar(255)") {
+		t.Error("varchar(100) and varchar(255) normalized to the same type")
+	}
+	if normalizeType("numeric(10,2)") == normalizeType("numeric") {
+		t.Error("numeric(10,2) and numeric normalized to the same type")
+	}
+}
+
+// PostgreSQL reports NO ACTION where Ent and GORM leave the action out.
+func TestForeignKeyDefaultAction(t *testing.T) {
+	if foreignKey("customer_id", "customers", "id", "") != foreignKey("customer_id", "customers", "id", "NO ACTION") {
+		t.Error("a foreign key without an action differs from NO ACTION")
+	}
+	if foreignKey("order_id", "orders", "id", "cascade") != foreignKey("order_id", "orders", "id", "CASCADE") {
+		t.Error("the ON DELETE action is case sensitive")
+	}
+}
+
+func subjects(differences []difference) []string {
+	var s []string
+	for _, d := range differences {
+		s = append(s, d.subject)
+	}
+
+	return s
+}
+
+// A table one source lacks is told apart from a column it lacks.
+func TestCompareMissingTableAndColumn(t *testing.T) {
+	id := Column{Type: "integer"}
+	sources := []source{
+		{name: "database", schema: Schema{"orders": {Columns: map[string]Column{"id": id, "status": {Type: "varchar(20)"}}}}},
+		{name: "ent", schema: Schema{"orders": {Columns: map[string]Column{"id": id}}}},
+		{name: "gorm", schema: Schema{}},
+	}
+
+	differences := compare(sources)
+	want := []string{"orders.id type", "orders.id nullability", "orders.status type", "orders.status nullability"}
+	if got := subjects(differences); !slices.Equal(got, want) {
+		t.Fatalf("compare found %q, want %q", got, want)
+	}
+
+	if got := differences[2].values; !slices.Equal(got, []string{"varchar(20)", "missing", "missing table"}) {
+		t.Errorf("orders.status type values = %q", got)
+	}
+}
+
+// Only the source that disagrees makes a difference; the per-source values show which one it is.
+func TestCompareSingleDissenter(t *testing.T) {
+	email := func(nullable bool) *Table {
+		return &Table{
+			Columns: map[string]Column{"email": {Type: "varchar(100)", Nullable: nullable}},
+			Indexes: []string{index("UNIQUE", "email")},
+		}
+	}
+	sources := []source{
+		{name: "database", schema: Schema{"customers": email(false)}},
+		{name: "ent", schema: Schema{"customers": email(false)}},
+		{name: "gorm", schema: Schema{"customers": email(true)}},
+	}
+
+	differences := compare(sources)
+	if len(differences) != 1 || differences[0].subject != "customers.email nullability" {
+		t.Fatalf("compare found %q, want only the email nullability", subjects(differences))
+	}
+	if got := differences[0].values; !slices.Equal(got, []string{"NOT NULL", "NOT NULL", "NULL"}) {
+		t.Errorf("nullability values = %q", got)
+	}
+}
+
+// Composite indexes compare with their column order.
+func TestCompareIndexColumnOrder(t *testing.T) {
+	sources := []source{
+		{name: "database", schema: Schema{"order_items": {Indexes: []string{index("INDEX", "order_id", "product_id")}}}},
+		{name: "ent", schema: Schema{"order_items": {Indexes: []string{index("INDEX", "product_id", "order_id")}}}},
+	}
+
+	want := []string{"order_items index INDEX (order_id, product_id)", "order_items index INDEX (product_id, order_id)"}
+	if got := subjects(compare(sources)); !slices.Equal(got, want) {
+		t.Errorf("compare found %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,508 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/bootstrap"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/migrations"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+const (
+	productPrice  models.Money = 9999
+	orderQuantity              = 2
+)
+
+// PGX runs the operations on the pool directly. The database/sql handle wraps the same pool
+// and is only used by the shared helpers and the reference checks.
+type PGX struct {
+	pool *pgxpool.Pool
+	db   *sql.DB
+}
+
+type CreateProduct struct {
+	lastID int
+	PGX
+}
+
+func (op *CreateProduct) Name() string {
+	return "Create Product"
+}
+
+func (op *CreateProduct) Execute(iteration int) error {
+	_, err := op.pool.
+		Exec(
+			context.Background(),
+			"INSERT INTO products (name, description, price, stock) VALUES ($1, $2, $3, $4)",
+			fmt.Sprintf("Product_%d", iteration),
+			"Test product",
+			productPrice,
+			100,
+		)
+
+	return err
+}
+
+func (op *CreateProduct) Setup() error {
+	return op.pool.
+		QueryRow(context.Background(), "SELECT COALESCE(MAX(id), 0) FROM products").
+		Scan(&op.lastID)
+}
+
+func (op *CreateProduct) Teardown() error {
+	_, err := op.pool.Exec(context.Background(), "DELETE FROM products WHERE id > $1", op.lastID)
+
+	return err
+}
+
+type GetCustomerByID struct {
+	IDs    utils.Generator
+	id     int
+	result models.Customer
+	PGX
+}
+
+func (op *GetCustomerByID) Name() string {
+	return "Get Customer by ID"
+}
+
+func (op *GetCustomerByID) Execute(int) error {
+	op.id = op.IDs.Next()
+	return op.pool.
+		QueryRow(
+			context.Background(),
+			"SELECT id, name, email, created_at FROM customers WHERE id = $1",
+			op.id,
+		).
+		Scan(
+			&op.result.ID,
+			&op.result.Name,
+			&op.result.Email,
+			&op.result.CreatedAt,
+		)
+}
+
+func (op *GetCustomerByID) Check(int) error {
+	return reference.CheckCustomer(context.Background(), op.db, op.id, op.result)
+}
+
+type GetProductByID struct {
+	IDs utils.Generator
+	PGX
+}
+
+func (op *GetProductByID) Name() string {
+	return "Get Product by ID"
+}
+
+func (op *GetProductByID) Execute(int) error {
+	var product models.Product
+	return op.pool.
+		QueryRow(
+			context.Background(),
+			"SELECT id, name, description, price, stock, created_at FROM products WHERE id = $1",
+			op.IDs.Next(),
+		).
+		Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
+		)
+}
+
+type UpdateProductByID struct {
+	IDs   utils.Generator
+	Price models.Money
+	PGX
+}
+
+func (op *UpdateProductByID) Name() string {
+	return "Update Product Price by ID"
+}
+
+func (op *UpdateProductByID) Execute(int) error {
+	_, err := op.pool.
+		Exec(
+			context.Background(),
+			"UPDATE products SET price = $1 WHERE id = $2",
+			op.Price,
+			op.IDs.Next(),
+		)
+
+	return err
+}
+
+type DeleteProductByName struct {
+	PGX
+}
+
+func (op *DeleteProductByName) Name() string {
+	return "Delete Product by Name"
+}
+
+func (op *DeleteProductByName) BeforeEach(iteration int) error {
+	return (&CreateProduct{PGX: op.PGX}).Execute(iteration)
+}
+
+func (op *DeleteProductByName) Execute(iteration int) error {
+	_, err := op.pool.Exec(
+		context.Background(),
+		"DELETE FROM products WHERE name = $1",
+		fmt.Sprintf("Product_%d", iteration),
+	)
+
+	return err
+}
+
+type CreateOrderWithProductsByCustomerID struct {
+	CustomerIDs utils.Generator
+	ProductIDs  utils.Generator
+	lastID      int
+	PGX
+}
+
+func (op *CreateOrderWithProductsByCustomerID) Name() string {
+	return "Create Order with Products By Customer ID (Transaction)"
+}
+
+// Execute sends both inserts in one batch. The order product refers to the new order
+// through the orders sequence, which the batch advanced in the same session.
+func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
+	return pgx.BeginFunc(context.Background(), op.pool, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		batch.Queue(
+			"INSERT INTO orders (customer_id, total) VALUES ($1, $2)",
+			op.CustomerIDs.Next(),
+			productPrice.Mul(orderQuantity),
+		)
+		batch.Queue(
+			"INSERT INTO order_products (order_id, product_id, quantity, price) VALUES (currval(pg_get_serial_sequence('orders', 'id')), $1, $2, $3)",
+			op.ProductIDs.Next(),
+			orderQuantity,
+			productPrice,
+		)
+
+		return tx.SendBatch(context.Background(), batch).Close()
+	})
+}
+
+func (op *CreateOrderWithProductsByCustomerID) Setup() error {
+	return op.pool.
+		QueryRow(context.Background(), "SELECT COALESCE(MAX(id), 0) FROM orders").
+		Scan(&op.lastID)
+}
+
+func (op *CreateOrderWithProductsByCustomerID) Teardown() error {
+	_, err := op.pool.Exec(context.Background(), "DELETE FROM orders WHERE id > $1", op.lastID)
+
+	return err
+}
+
+type CreateOrderWithProductsByCustomerIDCopyFrom struct {
+	CreateOrderWithProductsByCustomerID
+}
+
+func (op *CreateOrderWithProductsByCustomerIDCopyFrom) Name() string {
+	return op.CreateOrderWithProductsByCustomerID.Name() + " (CopyFrom)"
+}
+
+func (op *CreateOrderWithProductsByCustomerIDCopyFrom) Execute(int) error {
+	return pgx.BeginFunc(context.Background(), op.pool, func(tx pgx.Tx) error {
+		var orderID int
+		if err := tx.
+			QueryRow(
+				context.Background(),
+				"INSERT INTO orders (customer_id, total) VALUES ($1, $2) RETURNING id",
+				op.CustomerIDs.Next(),
+				productPrice.Mul(orderQuantity),
+			).
+			Scan(&orderID); err != nil {
+			return err
+		}
+
+		_, err := tx.CopyFrom(
+			context.Background(),
+			pgx.Identifier{"order_products"},
+			[]string{"order_id", "product_id", "quantity", "price"},
+			pgx.CopyFromRows([][]any{
+				{orderID, op.ProductIDs.Next(), orderQuantity, productPrice},
+			}),
+		)
+
+		return err
+	})
+}
+
+type GetCustomerStatsByID struct {
+	CustomerIDs utils.Generator
+	customerID  int
+	result      reference.CustomerStats
+	PGX
+}
+
+func (op *GetCustomerStatsByID) Name() string {
+	return "Get Customer Stats by ID (Aggregation)"
+}
+
+func (op *GetCustomerStatsByID) Execute(int) error {
+	op.customerID = op.CustomerIDs.Next()
+	return op.pool.
+		QueryRow(
+			context.Background(),
+			`SELECT
+            COUNT(*) AS total_orders,
+            COALESCE(SUM(total), 0) AS total_spent
+        FROM orders
+        WHERE customer_id = $1`,
+			op.customerID,
+		).
+		Scan(
+			&op.result.TotalOrders,
+			&op.result.TotalSpent,
+		)
+}
+
+func (op *GetCustomerStatsByID) Check(int) error {
+	return reference.CheckCustomerStats(context.Background(), op.db, op.customerID, op.result)
+}
+
+type GetProductSalesByLimit struct {
+	Limit   int
+	results []reference.ProductSales
+	PGX
+}
+
+func (op *GetProductSalesByLimit) Name() string {
+	return "Get Product Sales by Limit (Complex Join)"
+}
+
+func (op *GetProductSalesByLimit) Execute(int) error {
+	rows, err := op.pool.
+		Query(
+			context.Background(),
+			`SELECT
+            product_id,
+            products.name AS product_name,
+            SUM(quantity) AS total_sales,
+            SUM(order_products.price * quantity) AS revenue
+        FROM order_products
+        JOIN products ON products.id = order_products.product_id
+        GROUP BY product_id, products.name
+        ORDER BY revenue DESC
+        LIMIT $1`,
+			op.Limit,
+		)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.results = op.results[:0]
+	for rows.Next() {
+		var r reference.ProductSales
+		if err := rows.Scan(
+			&r.ProductID,
+			&r.ProductName,
+			&r.TotalSales,
+			&r.Revenue,
+		); err != nil {
+			return err
+		}
+		op.results = append(op.results, r)
+	}
+
+	return rows.Err()
+}
+
+func (op *GetProductSalesByLimit) Check(int) error {
+	return reference.CheckProductSales(context.Background(), op.db, op.Limit, op.results)
+}
+
+func newPool(dsn string, mode pgx.QueryExecMode) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	config.ConnConfig.DefaultQueryExecMode = mode
+
+	return pgxpool.NewWithConfig(context.Background(), config)
+}
+
+func selectIDs(pool *pgxpool.Pool, query string) ([]int, error) {
+	rows, err := pool.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// nativeConfig overrides -driver, which only applies to the database/sql implementations.
+// The operations use the pool directly, the bootstrap and the checks go through pgx's database/sql driver.
+func nativeConfig(config utils.Config) utils.Config {
+	config.Driver = "pgx"
+	config.DriverLabel = "pgx (native)"
+
+	return config
+}
+
+func main() {
+	config := nativeConfig(utils.ParseConfig())
+
+	schema, err := bootstrap.Start(context.Background(), config)
+	if err != nil {
+		log.Fatalf("failed to prepare database: %v", err)
+	}
+
+	// The base operations parse and plan every statement like the database/sql implementations do,
+	// the statement cache variants run on a second pool that prepares each statement once per connection.
+	pool, err := newPool(schema.DSN, pgx.QueryExecModeExec)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+	defer pool.Close()
+
+	cachedPool, err := newPool(schema.DSN, pgx.QueryExecModeCacheStatement)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+	defer cachedPool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pool.Ping(ctx); err != nil {
+		log.Fatalf("failed to ping database: %v", err)
+	}
+
+	db := stdlib.OpenDBFromPool(pool)
+	defer db.Close()
+
+	if err := migrations.Check(ctx, db); err != nil {
+		log.Fatalf("failed to check schema version: %v", err)
+	}
+
+	metadata, err := dataset.Describe(ctx, db)
+	if err != nil {
+		log.Fatalf("failed to describe dataset: %v", err)
+	}
+
+	customerIDs, err := selectIDs(pool, "SELECT id FROM customers ORDER BY id")
+	if err != nil {
+		log.Fatalf("failed to get customers: %v", err)
+	}
+
+	productIDs, err := selectIDs(pool, "SELECT id FROM products ORDER BY id")
+	if err != nil {
+		log.Fatalf("failed to get products: %v", err)
+	}
+
+	generator := func(ids []int, stream uint64) utils.Generator {
+		g, err := config.Generator(ids, stream)
+		if err != nil {
+			log.Fatalf("failed to create generator: %v", err)
+		}
+
+		return g
+	}
+
+	operations := []utils.Operation{
+		&CreateProduct{
+			PGX: PGX{pool, db},
+		},
+		&GetCustomerByID{
+			IDs: generator(customerIDs, 0),
+			PGX: PGX{pool, db},
+		},
+		&GetProductByID{
+			IDs: generator(productIDs, 1),
+			PGX: PGX{pool, db},
+		},
+		&UpdateProductByID{
+			IDs:   generator(productIDs, 1),
+			Price: 8999,
+			PGX:   PGX{pool, db},
+		},
+		&CreateOrderWithProductsByCustomerID{
+			CustomerIDs: generator(customerIDs, 0),
+			ProductIDs:  generator(productIDs, 1),
+			PGX:         PGX{pool, db},
+		},
+		&CreateOrderWithProductsByCustomerIDCopyFrom{
+			CreateOrderWithProductsByCustomerID: CreateOrderWithProductsByCustomerID{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				PGX:         PGX{pool, db},
+			},
+		},
+		&GetCustomerStatsByID{
+			CustomerIDs: generator(customerIDs, 0),
+			PGX:         PGX{pool, db},
+		},
+		&GetProductSalesByLimit{
+			Limit: 10,
+			PGX:   PGX{pool, db},
+		},
+		&DeleteProductByName{
+			PGX: PGX{pool, db},
+		},
+	}
+
+	cached := PGX{cachedPool, db}
+	operations = append(
+		operations,
+		utils.Variant{
+			Operation: &CreateProduct{PGX: cached},
+			Suffix:    "Statement Cache",
+		},
+		utils.Variant{
+			Operation: &GetCustomerByID{IDs: generator(customerIDs, 0), PGX: cached},
+			Suffix:    "Statement Cache",
+		},
+		utils.Variant{
+			Operation: &GetProductByID{IDs: generator(productIDs, 1), PGX: cached},
+			Suffix:    "Statement Cache",
+		},
+		utils.Variant{
+			Operation: &UpdateProductByID{IDs: generator(productIDs, 1), Price: 8999, PGX: cached},
+			Suffix:    "Statement Cache",
+		},
+		utils.Variant{
+			Operation: &CreateOrderWithProductsByCustomerID{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				PGX:         cached,
+			},
+			Suffix: "Statement Cache",
+		},
+		utils.Variant{
+			Operation: &GetCustomerStatsByID{CustomerIDs: generator(customerIDs, 0), PGX: cached},
+			Suffix:    "Statement Cache",
+		},
+		utils.Variant{
+			Operation: &GetProductSalesByLimit{Limit: 10, PGX: cached},
+			Suffix:    "Statement Cache",
+		},
+		utils.Variant{
+			Operation: &DeleteProductByName{PGX: cached},
+			Suffix:    "Statement Cache",
+		},
+	)
+
+	if err := config.Run(operations, slices.Concat(config.Labels(), schema.Labels(), metadata.Labels())...); err != nil {
+		log.Fatalf("failed to run operations: %v", err)
+	}
+}
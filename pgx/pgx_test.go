@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/bootstrap"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// The pgx binary reports its own driver label, but the bootstrap opens its connections with config.Driver,
+// which has to name a registered driver whatever -driver was set to.
+func TestNativeConfigOpensDB(t *testing.T) {
+	for _, driver := range []string{"pq", "pgx", ""} {
+		config := nativeConfig(utils.Config{Driver: driver})
+
+		db, err := config.OpenDB(utils.DefaultDSN)
+		if err != nil {
+			t.Errorf("-driver=%q: OpenDB failed: %v", driver, err)
+			continue
+		}
+		db.Close()
+
+		if got := config.Labels()[0]; got.Value != "pgx (native)" {
+			t.Errorf("-driver=%q: labelled %s %q, want pgx (native)", driver, got.Name, got.Value)
+		}
+	}
+}
+
+// TestPrepareNativeConfig runs the bootstrap of every schema source with the pgx binary's config.
+// The ent and gorm sources recreate their schemas, so it only runs against the database in PGX_DSN.
+func TestPrepareNativeConfig(t *testing.T) {
+	dsn := os.Getenv("PGX_DSN")
+	if dsn == "" {
+		t.Skip("PGX_DSN is not set")
+	}
+
+	for _, source := range []string{"migrations", "ent", "gorm"} {
+		t.Run(source, func(t *testing.T) {
+			config := nativeConfig(utils.Config{
+				DSN:     dsn,
+				Driver:  "pq",
+				Schema:  source,
+				Results: t.TempDir(),
+			})
+
+			schema, err := bootstrap.Prepare(context.Background(), config)
+			if err != nil {
+				t.Fatalf("Prepare failed: %v", err)
+			}
+
+			db, err := config.OpenDB(schema.DSN)
+			if err != nil {
+				t.Fatalf("failed to open the %s schema: %v", source, err)
+			}
+			defer db.Close()
+
+			var products int
+			if err := db.QueryRow("SELECT count(*) FROM products").Scan(&products); err != nil {
+				t.Fatalf("failed to query the %s schema: %v", source, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,455 @@
+package reference
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// The sql tags name the result columns for Ent's Scan.
+
+type CustomerStats struct {
+	TotalOrders int64        `sql:"total_orders"`
+	TotalSpent  models.Money `sql:"total_spent"`
+}
+
+type ProductSales struct {
+	ProductID   int          `sql:"product_id"`
+	ProductName string       `sql:"product_name"`
+	TotalSales  int64        `sql:"total_sales"`
+	Revenue     models.Money `sql:"revenue"`
+}
+
+// CheckCustomer compares a customer read by an operation with the row selected by id with plain SQL.
+func CheckCustomer(ctx context.Context, db *sql.DB, id int, got models.Customer) error {
+	var want models.Customer
+	if err := db.
+		QueryRowContext(ctx, "SELECT id, name, email, created_at FROM customers WHERE id = $1", id).
+		Scan(&want.ID, &want.Name, &want.Email, &want.CreatedAt); err != nil {
+		return fmt.Errorf("failed to read reference customer %d: %w", id, err)
+	}
+
+	if got.ID != want.ID || got.Name != want.Name || got.Email != want.Email || !got.CreatedAt.Equal(want.CreatedAt) {
+		return fmt.Errorf("customer %d is %+v, want %+v", id, got, want)
+	}
+
+	return nil
+}
+
+// CheckCustomerStats compares the order count and total spent of a customer with a plain SQL aggregation.
+func CheckCustomerStats(ctx context.Context, db *sql.DB, customerID int, got CustomerStats) error {
+	var want CustomerStats
+	if err := db.
+		QueryRowContext(
+			ctx,
+			"SELECT COUNT(*), COALESCE(SUM(total), 0) FROM orders WHERE customer_id = $1",
+			customerID,
+		).
+		Scan(&want.TotalOrders, &want.TotalSpent); err != nil {
+		return fmt.Errorf("failed to read reference stats of customer %d: %w", customerID, err)
+	}
+
+	if got != want {
+		return fmt.Errorf("stats of customer %d are %+v, want %+v", customerID, got, want)
+	}
+
+	return nil
+}
+
+// CheckProductSales compares a top-N list of product sales with the sales of every product.
+// Products with equal revenue may be returned in any order, so every row must match the product's own
+// sales and the revenues must match the reference top-N one by one.
+func CheckProductSales(ctx context.Context, db *sql.DB, limit int, got []ProductSales) error {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT op.product_id, p.name, SUM(op.quantity), SUM(op.price * op.quantity) AS revenue
+        FROM order_products op
+        JOIN products p ON p.id = op.product_id
+        GROUP BY op.product_id, p.name
+        ORDER BY revenue DESC, op.product_id`,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read reference product sales: %w", err)
+	}
+	defer rows.Close()
+
+	var want []ProductSales
+	for rows.Next() {
+		var s ProductSales
+		if err := rows.Scan(&s.ProductID, &s.ProductName, &s.TotalSales, &s.Revenue); err != nil {
+			return err
+		}
+		want = append(want, s)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	sales := make(map[int]ProductSales, len(want))
+	for _, s := range want {
+		sales[s.ProductID] = s
+	}
+
+	want = want[:min(limit, len(want))]
+	if len(got) != len(want) {
+		return fmt.Errorf("got %d product sales, want %d", len(got), len(want))
+	}
+
+	for i, s := range got {
+		if s != sales[s.ProductID] {
+			return fmt.Errorf("sales of product %d are %+v, want %+v", s.ProductID, s, sales[s.ProductID])
+		}
+		if s.Revenue != want[i].Revenue {
+			return fmt.Errorf("revenue at position %d is %s, want %s", i+1, s.Revenue, want[i].Revenue)
+		}
+	}
+
+	return nil
+}
+
+// GraphCustomers is the number of customers the customer graph operations load with their orders,
+// line items and products per iteration.
+const GraphCustomers = 10
+
+// NextGraphCustomers draws the customers of one customer graph iteration, all of them in datasets
+// with fewer than GraphCustomers. The operations draw them before the timed section and keep what they
+// loaded for CheckCustomerGraph.
+func NextGraphCustomers(g utils.Generator) ([]int, error) {
+	return utils.NextDistinct(g, min(GraphCustomers, g.Len()))
+}
+
+// CheckCustomerGraph compares customers loaded with their orders, line items and products with the
+// order lines of the same customers joined in plain SQL. Every customer, order and line item is reduced to
+// a customer/order/line/product path, so the order in which they were loaded does not matter.
+func CheckCustomerGraph(ctx context.Context, db *sql.DB, ids []int, got []models.Customer) error {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT c.id, o.id, op.id, op.product_id
+        FROM customers c
+        LEFT JOIN orders o ON o.customer_id = c.id
+        LEFT JOIN order_products op ON op.order_id = o.id
+        WHERE c.id = ANY($1)`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read reference customer graph: %w", err)
+	}
+	defer rows.Close()
+
+	var want []string
+	for rows.Next() {
+		var customerID int
+		var orderID, lineID, productID sql.NullInt64
+		if err := rows.Scan(&customerID, &orderID, &lineID, &productID); err != nil {
+			return err
+		}
+		want = append(want, fmt.Sprintf("%d/%d/%d/%d", customerID, orderID.Int64, lineID.Int64, productID.Int64))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var paths []string
+	for _, c := range got {
+		if len(c.Orders) == 0 {
+			paths = append(paths, fmt.Sprintf("%d/0/0/0", c.ID))
+		}
+		for _, o := range c.Orders {
+			if o.CustomerID != c.ID {
+				return fmt.Errorf("order %d of customer %d belongs to customer %d", o.ID, c.ID, o.CustomerID)
+			}
+			if len(o.Products) == 0 {
+				paths = append(paths, fmt.Sprintf("%d/%d/0/0", c.ID, o.ID))
+			}
+			for _, line := range o.Products {
+				if line.Product.ID != line.ProductID {
+					return fmt.Errorf("line item %d refers to product %d but holds product %d", line.ID, line.ProductID, line.Product.ID)
+				}
+				paths = append(paths, fmt.Sprintf("%d/%d/%d/%d", c.ID, o.ID, line.ID, line.Product.ID))
+			}
+		}
+	}
+
+	slices.Sort(want)
+	slices.Sort(paths)
+	if !slices.Equal(paths, want) {
+		return fmt.Errorf("loaded %d customer graph paths, want %d matching the reference", len(paths), len(want))
+	}
+
+	return nil
+}
+
+// CheckCustomerByEmail checks that the customer with the email exists and has the name written by an upsert.
+func CheckCustomerByEmail(ctx context.Context, db *sql.DB, email, name string) error {
+	var got string
+	if err := db.
+		QueryRowContext(ctx, "SELECT name FROM customers WHERE email = $1", email).
+		Scan(&got); err != nil {
+		return fmt.Errorf("failed to read reference customer %s: %w", email, err)
+	}
+
+	if got != name {
+		return fmt.Errorf("customer %s is named \"%s\", want \"%s\"", email, got, name)
+	}
+
+	return nil
+}
+
+// CheckProductWalk compares the number of products read by a walk through all pages and the sum of their ids
+// with the products table, so a skipped or repeated row is noticed without keeping every id.
+func CheckProductWalk(ctx context.Context, db *sql.DB, rows int, idSum int64) error {
+	var wantRows int
+	var wantSum int64
+	if err := db.
+		QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(id), 0) FROM products").
+		Scan(&wantRows, &wantSum); err != nil {
+		return fmt.Errorf("failed to read reference products: %w", err)
+	}
+
+	if rows != wantRows || idSum != wantSum {
+		return fmt.Errorf("walk read %d products with id sum %d, want %d with id sum %d", rows, idSum, wantRows, wantSum)
+	}
+
+	return nil
+}
+
+// CheckInventory checks the orders placed by reservations after lastOrderID against the stock of the products
+// before them: every product must have its initial stock less the quantities ordered and no less than zero,
+// and every order total must be the sum of its line items.
+func CheckInventory(ctx context.Context, db *sql.DB, stock map[int]int, lastOrderID int) error {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT p.id, p.stock, COALESCE(SUM(op.quantity), 0)
+        FROM products p
+        LEFT JOIN order_products op ON op.product_id = p.id AND op.order_id > $1
+        GROUP BY p.id`,
+		lastOrderID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read reference stock: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, left, ordered int
+		if err := rows.Scan(&id, &left, &ordered); err != nil {
+			return err
+		}
+		if left < 0 || left+ordered != stock[id] {
+			return fmt.Errorf("product %d has %d in stock after %d were ordered, want %d", id, left, ordered, stock[id]-ordered)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var id int
+	var total, lines models.Money
+	err = db.
+		QueryRowContext(
+			ctx,
+			`SELECT o.id, o.total, COALESCE(SUM(op.price * op.quantity), 0)
+            FROM orders o
+            LEFT JOIN order_products op ON op.order_id = o.id
+            WHERE o.id > $1
+            GROUP BY o.id
+            HAVING o.total <> COALESCE(SUM(op.price * op.quantity), 0)
+            LIMIT 1`,
+			lastOrderID,
+		).
+		Scan(&id, &total, &lines)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to read reference order totals: %w", err)
+	default:
+		return fmt.Errorf("order %d has total %s, want %s", id, total, lines)
+	}
+}
+
+// OrderProductStream is what the streaming operations keep of the first Limit order lines by id: their number
+// and revenue, never the lines themselves, so only the way an operation reads them decides the memory it holds.
+// The operations find LastID, the id of the last of those lines, and N, their number, in Setup, so every
+// execution bounds its read by id instead of LIMIT.
+type OrderProductStream struct {
+	Limit   int
+	LastID  int
+	N       int
+	rows    int
+	revenue models.Money
+}
+
+// Rows returns the number of lines every execution reads.
+func (s *OrderProductStream) Rows() int {
+	return s.N
+}
+
+// Reset clears the count at the start of an execution.
+func (s *OrderProductStream) Reset() {
+	s.rows, s.revenue = 0, 0
+}
+
+// Add counts one streamed line.
+func (s *OrderProductStream) Add(price models.Money, quantity int) {
+	s.rows++
+	s.revenue += price.Mul(quantity)
+}
+
+// Check compares the number of lines streamed by the last execution and their revenue
+// with a plain SQL aggregation of the lines up to LastID.
+func (s *OrderProductStream) Check(ctx context.Context, db *sql.DB) error {
+	var wantRows int
+	var wantRevenue models.Money
+	if err := db.
+		QueryRowContext(
+			ctx,
+			"SELECT COUNT(*), COALESCE(SUM(price * quantity), 0) FROM order_products WHERE id <= $1",
+			s.LastID,
+		).
+		Scan(&wantRows, &wantRevenue); err != nil {
+		return fmt.Errorf("failed to read reference order lines: %w", err)
+	}
+
+	if s.rows != wantRows || s.revenue != wantRevenue {
+		return fmt.Errorf("streamed %d order lines with revenue %s, want %d with revenue %s", s.rows, s.revenue, wantRows, wantRevenue)
+	}
+
+	return nil
+}
+
+// CheckProductSearch compares the ids of the products found by a search with a static query that skips
+// the filters whose parameters are NULL.
+func CheckProductSearch(ctx context.Context, db *sql.DB, f utils.ProductFilter, limit int, got []int) error {
+	var name *string
+	if f.Name != "" {
+		pattern := "%" + f.Name + "%"
+		name = &pattern
+	}
+
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT id FROM products
+        WHERE ($1::numeric IS NULL OR price >= $1)
+        AND ($2::numeric IS NULL OR price <= $2)
+        AND (NOT $3 OR stock > 0)
+        AND ($4::text IS NULL OR name ILIKE $4)
+        AND ($5::timestamptz IS NULL OR created_at >= $5)
+        AND ($6::timestamptz IS NULL OR created_at < $6)
+        ORDER BY id
+        LIMIT $7`,
+		f.MinPrice, f.MaxPrice, f.InStock, name, f.CreatedFrom, f.CreatedTo, limit,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read reference search: %w", err)
+	}
+	defer rows.Close()
+
+	var want []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		want = append(want, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !slices.Equal(got, want) {
+		return fmt.Errorf("search found products %v, want %v", got, want)
+	}
+
+	return nil
+}
+
+// The update operations raise the price of products by 10% and add Restock to their stock with expressions
+// evaluated by the database. The products are restored after every iteration outside the timed section,
+// so every iteration updates the seeded values.
+const (
+	// Restock is the number of units the expression updates add to the stock of every product.
+	Restock = 5
+	// UpdateBatch is the number of products updated by id in one statement.
+	UpdateBatch = 50
+	// CheapPrice bounds the price band the set-based update selects products by.
+	CheapPrice models.Money = 2000
+)
+
+// NextUpdateBatch draws the products of one batched update, all of them in datasets with fewer than UpdateBatch.
+func NextUpdateBatch(g utils.Generator) ([]int, error) {
+	return utils.NextDistinct(g, min(UpdateBatch, g.Len()))
+}
+
+// CheckProductsRepriced checks that the products with the ids hold their price before raised by 10% and
+// their stock before increased by Restock, as set by price = price * 1.1 and stock = stock + Restock.
+func CheckProductsRepriced(ctx context.Context, db *sql.DB, before map[int]models.Product, ids []int) error {
+	rows, err := db.QueryContext(ctx, "SELECT id, price, stock FROM products WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to read reference products: %w", err)
+	}
+	defer rows.Close()
+
+	var n int
+	for rows.Next() {
+		var p models.Product
+		if err := rows.Scan(&p.ID, &p.Price, &p.Stock); err != nil {
+			return err
+		}
+		n++
+
+		// NUMERIC(10, 2) rounds the third decimal of price * 1.1 half away from zero.
+		wantPrice := (before[p.ID].Price*11 + 5) / 10
+		wantStock := before[p.ID].Stock + Restock
+		if p.Price != wantPrice || p.Stock != wantStock {
+			return fmt.Errorf("product %d has price %s and stock %d, want %s and %d", p.ID, p.Price, p.Stock, wantPrice, wantStock)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if n != len(ids) {
+		return fmt.Errorf("found %d of %d updated products", n, len(ids))
+	}
+
+	return nil
+}
+
+// CheckCustomerDeleted verifies that a customer and every order with its lines in the range of order IDs
+// created for them are gone.
+func CheckCustomerDeleted(ctx context.Context, db *sql.DB, customerID, firstOrderID, lastOrderID int) error {
+	var customers, orders, lines int
+	if err := db.
+		QueryRowContext(
+			ctx,
+			`SELECT
+            (SELECT COUNT(*) FROM customers WHERE id = $1),
+            (SELECT COUNT(*) FROM orders WHERE customer_id = $1 OR id BETWEEN $2 AND $3),
+            (SELECT COUNT(*) FROM order_products WHERE order_id BETWEEN $2 AND $3)`,
+			customerID,
+			firstOrderID,
+			lastOrderID,
+		).
+		Scan(&customers, &orders, &lines); err != nil {
+		return fmt.Errorf("failed to read reference customer %d: %w", customerID, err)
+	}
+
+	if customers != 0 || orders != 0 || lines != 0 {
+		return fmt.Errorf(
+			"customer %d left %d customer, %d order and %d order product rows behind",
+			customerID,
+			customers,
+			orders,
+			lines,
+		)
+	}
+
+	return nil
+}
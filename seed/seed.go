@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/isolation"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/migrations"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+func main() {
+	var dsn string
+	var options dataset.Options
+	flag.StringVar(&dsn, "dsn", utils.DefaultDSN, "PostgreSQL connection string")
+	flag.Float64Var(&options.Scale, "scale", 1, "scale factor of the dataset (1 = 1000 customers, 500 products)")
+	flag.Uint64Var(&options.Seed, "seed", 1, "seed of the dataset generator")
+	flag.Parse()
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := migrations.Check(ctx, db); err != nil {
+		log.Fatalf("failed to check schema version: %v", err)
+	}
+
+	start := time.Now()
+	metadata, err := dataset.Load(ctx, db, options)
+	if err != nil {
+		log.Fatalf("failed to load dataset: %v", err)
+	}
+
+	if err := isolation.Discard(ctx, dsn); err != nil {
+		log.Fatalf("failed to discard isolation baseline: %v", err)
+	}
+
+	utils.PrintLabels(metadata.Labels()...)
+	log.Printf("dataset loaded in %s", time.Since(start).Round(time.Millisecond))
+}
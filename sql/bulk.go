@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// CreateProductsInBulk imports products with multi-row INSERT statements of BatchSize rows each,
+// all in one transaction.
+type CreateProductsInBulk struct {
+	BatchSize int
+	CreateProduct
+}
+
+func (op *CreateProductsInBulk) Name() string {
+	return "Create Products in Bulk"
+}
+
+func (op *CreateProductsInBulk) Rows() int {
+	return utils.BulkProducts
+}
+
+// BeforeEach deletes the products of the previous iteration.
+func (op *CreateProductsInBulk) BeforeEach(int) error {
+	return op.Teardown()
+}
+
+func (op *CreateProductsInBulk) Execute(iteration int) error {
+	tx, err := op.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < utils.BulkProducts; start += op.BatchSize {
+		end := min(start+op.BatchSize, utils.BulkProducts)
+
+		var query strings.Builder
+		query.WriteString("INSERT INTO products (name, description, price, stock) VALUES ")
+
+		args := make([]any, 0, (end-start)*4)
+		for i := start; i < end; i++ {
+			if i > start {
+				query.WriteString(", ")
+			}
+			n := len(args)
+			fmt.Fprintf(&query, "($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+			args = append(args, fmt.Sprintf("Product_%d_%d", iteration, i), "Test product", productPrice, 100)
+		}
+
+		if _, err := tx.Exec(query.String(), args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CreateProductsInBulkCopy imports the same products with one COPY FROM STDIN, which streams every row
+// regardless of the batch size.
+type CreateProductsInBulkCopy struct {
+	CreateProductsInBulk
+}
+
+func (op *CreateProductsInBulkCopy) Name() string {
+	return op.CreateProductsInBulk.Name() + " (COPY)"
+}
+
+func (op *CreateProductsInBulkCopy) Execute(iteration int) error {
+	tx, err := op.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(pq.CopyIn("products", "name", "description", "price", "stock"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i := 0; i < utils.BulkProducts; i++ {
+		if _, err := stmt.Exec(fmt.Sprintf("Product_%d_%d", iteration, i), "Test product", productPrice, 100); err != nil {
+			return err
+		}
+	}
+
+	if _, err := stmt.Exec(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// customerDelete is embedded by the SQL delete operations, which run their statements on the same connection
+// pool the history is created with.
+type customerDelete struct {
+	history dataset.History
+	utils.WALVolume
+	SQL
+}
+
+func (d *customerDelete) BeforeEach(iteration int) error {
+	ctx := context.Background()
+
+	var err error
+	d.history, err = dataset.CreateCustomerHistory(
+		ctx,
+		d.db,
+		fmt.Sprintf("Customer_%d", iteration),
+		fmt.Sprintf("customer_%d@history.test", iteration),
+		orderQuantity,
+	)
+	if err != nil {
+		return err
+	}
+
+	return d.Start(ctx, d.db)
+}
+
+func (d *customerDelete) Rows() int {
+	return dataset.HistoryRows
+}
+
+func (d *customerDelete) Check(int) error {
+	return reference.CheckCustomerDeleted(
+		context.Background(),
+		d.db,
+		d.history.CustomerID,
+		d.history.FirstOrderID,
+		d.history.LastOrderID,
+	)
+}
+
+func (d *customerDelete) AfterEach(int) error {
+	return d.Stop(context.Background(), d.db)
+}
+
+// DeleteCustomerWithOrdersCascade deletes only the customer and leaves the orders and their lines to the
+// ON DELETE CASCADE foreign keys.
+type DeleteCustomerWithOrdersCascade struct {
+	customerDelete
+}
+
+func (op *DeleteCustomerWithOrdersCascade) Name() string {
+	return "Delete Customer with Orders (Cascade)"
+}
+
+func (op *DeleteCustomerWithOrdersCascade) Execute(int) error {
+	_, err := op.db.Exec("DELETE FROM customers WHERE id = $1", op.history.CustomerID)
+
+	return err
+}
+
+// DeleteCustomerWithOrdersChildrenFirst deletes the order products, the orders and then the customer in one
+// transaction, so the cascades find nothing left to delete.
+type DeleteCustomerWithOrdersChildrenFirst struct {
+	customerDelete
+}
+
+func (op *DeleteCustomerWithOrdersChildrenFirst) Name() string {
+	return "Delete Customer with Orders (Children First)"
+}
+
+func (op *DeleteCustomerWithOrdersChildrenFirst) Execute(int) error {
+	tx, err := op.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"DELETE FROM order_products WHERE order_id IN (SELECT id FROM orders WHERE customer_id = $1)",
+		op.history.CustomerID,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM orders WHERE customer_id = $1", op.history.CustomerID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM customers WHERE id = $1", op.history.CustomerID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// customerGraph holds the customers of an iteration. database/sql sends exactly the queries
+// the operation runs, so the operations count them themselves.
+type customerGraph struct {
+	CustomerIDs utils.Generator
+	ids         []int
+	customers   []models.Customer
+	queries     int64
+	SQL
+}
+
+func (g *customerGraph) BeforeEach(int) error {
+	var err error
+	g.ids, err = reference.NextGraphCustomers(g.CustomerIDs)
+
+	return err
+}
+
+func (g *customerGraph) RoundTrips() int64 {
+	return g.queries
+}
+
+func (g *customerGraph) Check(int) error {
+	return reference.CheckCustomerGraph(context.Background(), g.db, g.ids, g.customers)
+}
+
+type LoadCustomerGraphNPlusOne struct {
+	customerGraph
+}
+
+func (op *LoadCustomerGraphNPlusOne) Name() string {
+	return "Load Customer Graph (N+1)"
+}
+
+func (op *LoadCustomerGraphNPlusOne) Execute(int) error {
+	op.customers = make([]models.Customer, len(op.ids))
+	for i, id := range op.ids {
+		c := &op.customers[i]
+
+		op.queries++
+		if err := op.db.
+			QueryRow("SELECT id, name, email, created_at FROM customers WHERE id = $1", id).
+			Scan(&c.ID, &c.Name, &c.Email, &c.CreatedAt); err != nil {
+			return err
+		}
+
+		var err error
+		if c.Orders, err = op.orders(c.ID); err != nil {
+			return err
+		}
+
+		for j := range c.Orders {
+			o := &c.Orders[j]
+			if o.Products, err = op.lines(o.ID); err != nil {
+				return err
+			}
+
+			for k := range o.Products {
+				line := &o.Products[k]
+
+				op.queries++
+				if err := op.db.
+					QueryRow("SELECT id, name, description, price, stock, created_at FROM products WHERE id = $1", line.ProductID).
+					Scan(
+						&line.Product.ID,
+						&line.Product.Name,
+						&line.Product.Description,
+						&line.Product.Price,
+						&line.Product.Stock,
+						&line.Product.CreatedAt,
+					); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (op *LoadCustomerGraphNPlusOne) orders(customerID int) ([]models.Order, error) {
+	op.queries++
+	rows, err := op.db.Query(
+		"SELECT id, customer_id, date, total, created_at FROM orders WHERE customer_id = $1 ORDER BY id",
+		customerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	for rows.Next() {
+		var o models.Order
+		if err := rows.Scan(&o.ID, &o.CustomerID, &o.Date, &o.Total, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+
+	return orders, rows.Err()
+}
+
+func (op *LoadCustomerGraphNPlusOne) lines(orderID int) ([]models.OrderProduct, error) {
+	op.queries++
+	rows, err := op.db.Query(
+		"SELECT id, order_id, product_id, quantity, price FROM order_products WHERE order_id = $1 ORDER BY id",
+		orderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.OrderProduct
+	for rows.Next() {
+		var line models.OrderProduct
+		if err := rows.Scan(&line.ID, &line.OrderID, &line.ProductID, &line.Quantity, &line.Price); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+type LoadCustomerGraphJoin struct {
+	customerGraph
+}
+
+func (op *LoadCustomerGraphJoin) Name() string {
+	return "Load Customer Graph (JOIN)"
+}
+
+// Execute loads the whole graph with one query and assembles it from the rows, which arrive ordered
+// by customer, order and line item. Customers without orders and orders without line items come back
+// with NULL in the joined columns.
+func (op *LoadCustomerGraphJoin) Execute(int) error {
+	op.queries++
+	rows, err := op.db.Query(
+		`SELECT
+            c.id, c.name, c.email, c.created_at,
+            o.id, o.date, o.total, o.created_at,
+            op.id, op.product_id, op.quantity, op.price,
+            p.name, p.description, p.price, p.stock, p.created_at
+        FROM customers c
+        LEFT JOIN orders o ON o.customer_id = c.id
+        LEFT JOIN order_products op ON op.order_id = o.id
+        LEFT JOIN products p ON p.id = op.product_id
+        WHERE c.id = ANY($1)
+        ORDER BY c.id, o.id, op.id`,
+		pq.Array(op.ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.customers = make([]models.Customer, 0, len(op.ids))
+	for rows.Next() {
+		var c models.Customer
+		var o struct {
+			ID        sql.NullInt64
+			Date      sql.NullTime
+			Total     sql.NullString
+			CreatedAt sql.NullTime
+		}
+		var line struct {
+			ID        sql.NullInt64
+			ProductID sql.NullInt64
+			Quantity  sql.NullInt64
+			Price     sql.NullString
+		}
+		var p struct {
+			Name        sql.NullString
+			Description sql.NullString
+			Price       sql.NullString
+			Stock       sql.NullInt64
+			CreatedAt   sql.NullTime
+		}
+		if err := rows.Scan(
+			&c.ID, &c.Name, &c.Email, &c.CreatedAt,
+			&o.ID, &o.Date, &o.Total, &o.CreatedAt,
+			&line.ID, &line.ProductID, &line.Quantity, &line.Price,
+			&p.Name, &p.Description, &p.Price, &p.Stock, &p.CreatedAt,
+		); err != nil {
+			return err
+		}
+
+		if n := len(op.customers); n == 0 || op.customers[n-1].ID != c.ID {
+			op.customers = append(op.customers, c)
+		}
+		customer := &op.customers[len(op.customers)-1]
+		if !o.ID.Valid {
+			continue
+		}
+
+		if n := len(customer.Orders); n == 0 || customer.Orders[n-1].ID != int(o.ID.Int64) {
+			total, err := models.ParseMoney(o.Total.String)
+			if err != nil {
+				return err
+			}
+			customer.Orders = append(customer.Orders, models.Order{
+				ID:         int(o.ID.Int64),
+				CustomerID: c.ID,
+				Date:       o.Date.Time,
+				Total:      total,
+				CreatedAt:  o.CreatedAt.Time,
+			})
+		}
+		order := &customer.Orders[len(customer.Orders)-1]
+		if !line.ID.Valid {
+			continue
+		}
+
+		linePrice, err := models.ParseMoney(line.Price.String)
+		if err != nil {
+			return err
+		}
+		productPrice, err := models.ParseMoney(p.Price.String)
+		if err != nil {
+			return err
+		}
+
+		product := models.Product{
+			ID:        int(line.ProductID.Int64),
+			Name:      p.Name.String,
+			Price:     productPrice,
+			Stock:     int(p.Stock.Int64),
+			CreatedAt: p.CreatedAt.Time,
+		}
+		if p.Description.Valid {
+			product.Description = &p.Description.String
+		}
+
+		order.Products = append(order.Products, models.OrderProduct{
+			ID:        int(line.ID.Int64),
+			OrderID:   order.ID,
+			ProductID: product.ID,
+			Product:   product,
+			Quantity:  int(line.Quantity.Int64),
+			Price:     linePrice,
+		})
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// reservedProducts is the number of distinct products every checkout reserves.
+const reservedProducts = 3
+
+type checkout struct {
+	customerID int
+	productIDs []int
+}
+
+// ReserveInventory runs a checkout in each of Workers concurrent transactions: it locks the products,
+// verifies and decrements their stock, and places the order with totals computed from the locked prices.
+// A checkout of a product out of stock is rolled back and counted as rejected.
+// AfterEach restores the stock of the products and deletes the orders outside the timed section,
+// so every iteration reserves from the initial stock.
+type ReserveInventory struct {
+	CustomerIDs utils.Generator
+	ProductIDs  utils.Generator
+	Workers     int
+	checkouts   []checkout
+	stock       map[int]int
+	reserved    []int
+	lastOrderID int
+	utils.Contention
+	SQL
+}
+
+func (op *ReserveInventory) Name() string {
+	return "Reserve Inventory (Checkout)"
+}
+
+func (op *ReserveInventory) Setup() error {
+	if err := op.db.
+		QueryRow("SELECT COALESCE(MAX(id), 0) FROM orders").
+		Scan(&op.lastOrderID); err != nil {
+		return err
+	}
+
+	rows, err := op.db.Query("SELECT id, stock FROM products")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.stock = make(map[int]int)
+	for rows.Next() {
+		var id, stock int
+		if err := rows.Scan(&id, &stock); err != nil {
+			return err
+		}
+		op.stock[id] = stock
+	}
+
+	return rows.Err()
+}
+
+func (op *ReserveInventory) BeforeEach(int) error {
+	op.checkouts = make([]checkout, op.Workers)
+	op.reserved = op.reserved[:0]
+	for w := range op.checkouts {
+		productIDs, err := utils.NextDistinct(op.ProductIDs, min(reservedProducts, op.ProductIDs.Len()))
+		if err != nil {
+			return err
+		}
+
+		op.checkouts[w] = checkout{
+			customerID: op.CustomerIDs.Next(),
+			productIDs: productIDs,
+		}
+		op.reserved = append(op.reserved, productIDs...)
+	}
+
+	return nil
+}
+
+func (op *ReserveInventory) Execute(int) error {
+	return op.reserve(true)
+}
+
+// reserve runs the checkouts of the iteration concurrently. Ordered checkouts lock their products in id order,
+// so concurrent checkouts of the same products wait for each other instead of deadlocking.
+func (op *ReserveInventory) reserve(ordered bool) error {
+	return utils.Parallel(op.Workers, func(w int) error {
+		return op.Reserve(func() (bool, error) {
+			return op.checkout(op.checkouts[w], ordered)
+		})
+	})
+}
+
+func (op *ReserveInventory) checkout(c checkout, ordered bool) (bool, error) {
+	tx, err := op.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var total models.Money
+	inStock := true
+	add := func(price models.Money, stock int) {
+		total += price.Mul(orderQuantity)
+		inStock = inStock && stock >= orderQuantity
+	}
+
+	if ordered {
+		var rows *sql.Rows
+		if err := op.Lock(func() (err error) {
+			rows, err = tx.Query("SELECT price, stock FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(c.productIDs))
+			return err
+		}); err != nil {
+			return false, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var price models.Money
+			var stock int
+			if err := rows.Scan(&price, &stock); err != nil {
+				return false, err
+			}
+			add(price, stock)
+		}
+		if err := rows.Err(); err != nil {
+			return false, err
+		}
+	} else {
+		for _, id := range c.productIDs {
+			var price models.Money
+			var stock int
+			if err := op.Lock(func() error {
+				return tx.
+					QueryRow("SELECT price, stock FROM products WHERE id = $1 FOR UPDATE", id).
+					Scan(&price, &stock)
+			}); err != nil {
+				return false, err
+			}
+			add(price, stock)
+		}
+	}
+	if !inStock {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE products SET stock = stock - $1 WHERE id = ANY($2)",
+		orderQuantity,
+		pq.Array(c.productIDs),
+	); err != nil {
+		return false, err
+	}
+
+	var orderID int
+	if err := tx.
+		QueryRow("INSERT INTO orders (customer_id, total) VALUES ($1, $2) RETURNING id", c.customerID, total).
+		Scan(&orderID); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO order_products (order_id, product_id, quantity, price)
+        SELECT $1, id, $2, price FROM products WHERE id = ANY($3)`,
+		orderID,
+		orderQuantity,
+		pq.Array(c.productIDs),
+	); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (op *ReserveInventory) Check(int) error {
+	return reference.CheckInventory(context.Background(), op.db, op.stock, op.lastOrderID)
+}
+
+func (op *ReserveInventory) AfterEach(int) error {
+	stocks := make([]int, len(op.reserved))
+	for i, id := range op.reserved {
+		stocks[i] = op.stock[id]
+	}
+
+	_, err := op.db.Exec(
+		`UPDATE products p SET stock = s.stock
+        FROM unnest($1::int[], $2::int[]) AS s(id, stock)
+        WHERE p.id = s.id`,
+		pq.Array(op.reserved),
+		pq.Array(stocks),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = op.db.Exec("DELETE FROM orders WHERE id > $1", op.lastOrderID)
+
+	return err
+}
+
+// ReserveInventoryUnordered locks the products of a checkout one by one in the order they were drawn,
+// so concurrent checkouts sharing products can deadlock and are retried.
+type ReserveInventoryUnordered struct {
+	ReserveInventory
+}
+
+func (op *ReserveInventoryUnordered) Name() string {
+	return op.ReserveInventory.Name() + " (Unordered Locks)"
+}
+
+func (op *ReserveInventoryUnordered) Execute(int) error {
+	return op.reserve(false)
+}
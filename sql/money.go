@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// The float64 variants run the same queries as their models.Money counterparts,
+// but bind and scan prices and totals as float64.
+
+const productPriceFloat64 = 99.99
+
+type GetProductByIDFloat64 struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDFloat64) Name() string {
+	return op.GetProductByID.Name() + " (float64)"
+}
+
+func (op *GetProductByIDFloat64) Execute(int) error {
+	var product struct {
+		ID          int
+		Name        string
+		Description *string
+		Price       float64
+		Stock       int
+		CreatedAt   time.Time
+	}
+
+	return op.db.
+		QueryRow(
+			"SELECT id, name, description, price, stock, created_at FROM products WHERE id = $1",
+			op.IDs.Next(),
+		).
+		Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
+		)
+}
+
+type UpdateProductByIDFloat64 struct {
+	IDs   utils.Generator
+	Price float64
+	SQL
+}
+
+func (op *UpdateProductByIDFloat64) Name() string {
+	return "Update Product Price by ID (float64)"
+}
+
+func (op *UpdateProductByIDFloat64) Execute(int) error {
+	_, err := op.db.
+		Exec(
+			"UPDATE products SET price = $1 WHERE id = $2",
+			op.Price,
+			op.IDs.Next(),
+		)
+
+	return err
+}
+
+type CreateOrderWithProductsByCustomerIDFloat64 struct {
+	CreateOrderWithProductsByCustomerID
+}
+
+func (op *CreateOrderWithProductsByCustomerIDFloat64) Name() string {
+	return op.CreateOrderWithProductsByCustomerID.Name() + " (float64)"
+}
+
+func (op *CreateOrderWithProductsByCustomerIDFloat64) Execute(int) error {
+	tx, err := op.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var orderID uint
+	err = tx.
+		QueryRow(
+			"INSERT INTO orders (customer_id, total) VALUES ($1, $2) RETURNING id",
+			op.CustomerIDs.Next(),
+			productPriceFloat64*orderQuantity,
+		).
+		Scan(&orderID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO order_products (order_id, product_id, quantity, price) VALUES ($1, $2, $3, $4)",
+		orderID,
+		op.ProductIDs.Next(),
+		orderQuantity,
+		productPriceFloat64,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type GetCustomerStatsByIDFloat64 struct {
+	result struct {
+		TotalOrders int64
+		TotalSpent  float64
+	}
+	GetCustomerStatsByID
+}
+
+func (op *GetCustomerStatsByIDFloat64) Name() string {
+	return op.GetCustomerStatsByID.Name() + " (float64)"
+}
+
+func (op *GetCustomerStatsByIDFloat64) Execute(int) error {
+	op.customerID = op.CustomerIDs.Next()
+	return op.db.
+		QueryRow(
+			`SELECT 
+            COUNT(*) AS total_orders, 
+            COALESCE(SUM(total), 0) AS total_spent 
+        FROM orders 
+        WHERE customer_id = $1`,
+			op.customerID,
+		).
+		Scan(
+			&op.result.TotalOrders,
+			&op.result.TotalSpent,
+		)
+}
+
+func (op *GetCustomerStatsByIDFloat64) Check(int) error {
+	return reference.CheckCustomerStats(
+		context.Background(),
+		op.db,
+		op.customerID,
+		reference.CustomerStats{
+			TotalOrders: op.result.TotalOrders,
+			TotalSpent:  models.MoneyFromFloat(op.result.TotalSpent),
+		},
+	)
+}
+
+type productSalesFloat64 struct {
+	ProductID   int
+	ProductName string
+	TotalSales  int64
+	Revenue     float64
+}
+
+type GetProductSalesByLimitFloat64 struct {
+	results []productSalesFloat64
+	GetProductSalesByLimit
+}
+
+func (op *GetProductSalesByLimitFloat64) Name() string {
+	return op.GetProductSalesByLimit.Name() + " (float64)"
+}
+
+func (op *GetProductSalesByLimitFloat64) Execute(int) error {
+	rows, err := op.db.
+		Query(
+			`SELECT 
+            product_id, 
+            products.name AS product_name, 
+            SUM(quantity) AS total_sales, 
+            SUM(order_products.price * quantity) AS revenue
+        FROM order_products
+        JOIN products ON products.id = order_products.product_id
+        GROUP BY product_id, products.name
+        ORDER BY revenue DESC
+        LIMIT $1`,
+			op.Limit,
+		)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.results = op.results[:0]
+	for rows.Next() {
+		var r productSalesFloat64
+		if err := rows.Scan(
+			&r.ProductID,
+			&r.ProductName,
+			&r.TotalSales,
+			&r.Revenue,
+		); err != nil {
+			return err
+		}
+		op.results = append(op.results, r)
+	}
+
+	return rows.Err()
+}
+
+func (op *GetProductSalesByLimitFloat64) Check(int) error {
+	results := make([]reference.ProductSales, len(op.results))
+	for i, r := range op.results {
+		results[i] = reference.ProductSales{
+			ProductID:   r.ProductID,
+			ProductName: r.ProductName,
+			TotalSales:  r.TotalSales,
+			Revenue:     models.MoneyFromFloat(r.Revenue),
+		}
+	}
+
+	return reference.CheckProductSales(context.Background(), op.db, op.Limit, results)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+)
+
+// The base Get Product by ID scans the nullable description into a *string. The variants scan it
+// into a sql.NullString, or let PostgreSQL turn NULL into '' so that a plain string is enough.
+
+type GetProductByIDNullString struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDNullString) Name() string {
+	return op.GetProductByID.Name() + " (sql.NullString)"
+}
+
+func (op *GetProductByIDNullString) Execute(int) error {
+	var product struct {
+		ID          int
+		Name        string
+		Description sql.NullString
+		Price       models.Money
+		Stock       int
+		CreatedAt   time.Time
+	}
+
+	return op.db.
+		QueryRow(
+			"SELECT id, name, description, price, stock, created_at FROM products WHERE id = $1",
+			op.IDs.Next(),
+		).
+		Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
+		)
+}
+
+type GetProductByIDCoalesce struct {
+	GetProductByID
+}
+
+func (op *GetProductByIDCoalesce) Name() string {
+	return op.GetProductByID.Name() + " (COALESCE)"
+}
+
+func (op *GetProductByIDCoalesce) Execute(int) error {
+	var product struct {
+		ID          int
+		Name        string
+		Description string
+		Price       models.Money
+		Stock       int
+		CreatedAt   time.Time
+	}
+
+	return op.db.
+		QueryRow(
+			"SELECT id, name, COALESCE(description, '') AS description, price, stock, created_at FROM products WHERE id = $1",
+			op.IDs.Next(),
+		).
+		Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
+		)
+}
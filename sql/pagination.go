@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// productWalk keeps the rows scanned by page and their id sum for the check.
+type productWalk struct {
+	rows  int
+	idSum int64
+	utils.PageTimes
+	SQL
+}
+
+func (w *productWalk) Check(int) error {
+	return reference.CheckProductWalk(context.Background(), w.db, w.rows, w.idSum)
+}
+
+// page runs the query of one page and returns the number of products on it and the id of the last one.
+func (w *productWalk) page(query string, args ...any) (n, last int, err error) {
+	rows, err := w.db.Query(query, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
+		); err != nil {
+			return 0, 0, err
+		}
+		n++
+		w.rows++
+		w.idSum += int64(product.ID)
+		last = product.ID
+	}
+
+	return n, last, rows.Err()
+}
+
+type PaginateProductsOffset struct {
+	productWalk
+}
+
+func (op *PaginateProductsOffset) Name() string {
+	return "Paginate Products (Offset)"
+}
+
+func (op *PaginateProductsOffset) Execute(int) error {
+	op.rows, op.idSum = 0, 0
+	for page := 0; ; page++ {
+		start := time.Now()
+		n, _, err := op.page(
+			"SELECT id, name, description, price, stock, created_at FROM products ORDER BY id LIMIT $1 OFFSET $2",
+			utils.PageSize,
+			page*utils.PageSize,
+		)
+		if err != nil {
+			return err
+		}
+		op.Add(page, time.Since(start))
+
+		if n < utils.PageSize {
+			return nil
+		}
+	}
+}
+
+type PaginateProductsKeyset struct {
+	productWalk
+}
+
+func (op *PaginateProductsKeyset) Name() string {
+	return "Paginate Products (Keyset)"
+}
+
+func (op *PaginateProductsKeyset) Execute(int) error {
+	op.rows, op.idSum = 0, 0
+	for page, last := 0, 0; ; page++ {
+		start := time.Now()
+		n, next, err := op.page(
+			"SELECT id, name, description, price, stock, created_at FROM products WHERE id > $1 ORDER BY id LIMIT $2",
+			last,
+			utils.PageSize,
+		)
+		if err != nil {
+			return err
+		}
+		op.Add(page, time.Since(start))
+
+		if n < utils.PageSize {
+			return nil
+		}
+		last = next
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+)
+
+// The prepared variants run the same statements as their base operations,
+// but prepare them once in Setup and close them in Close.
+
+func closeStmts(stmts ...*sql.Stmt) error {
+	var errs []error
+	for _, stmt := range stmts {
+		if stmt != nil {
+			errs = append(errs, stmt.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type CreateProductPrepared struct {
+	stmt *sql.Stmt
+	CreateProduct
+}
+
+func (op *CreateProductPrepared) Name() string {
+	return op.CreateProduct.Name() + " (Prepared)"
+}
+
+func (op *CreateProductPrepared) Setup() (err error) {
+	if err := op.CreateProduct.Setup(); err != nil {
+		return err
+	}
+
+	op.stmt, err = op.db.Prepare("INSERT INTO products (name, description, price, stock) VALUES ($1, $2, $3, $4)")
+
+	return err
+}
+
+func (op *CreateProductPrepared) Execute(iteration int) error {
+	_, err := op.stmt.
+		Exec(
+			fmt.Sprintf("Product_%d", iteration),
+			"Test product",
+			productPrice,
+			100,
+		)
+
+	return err
+}
+
+func (op *CreateProductPrepared) Close() error {
+	return closeStmts(op.stmt)
+}
+
+type GetCustomerByIDPrepared struct {
+	stmt *sql.Stmt
+	GetCustomerByID
+}
+
+func (op *GetCustomerByIDPrepared) Name() string {
+	return op.GetCustomerByID.Name() + " (Prepared)"
+}
+
+func (op *GetCustomerByIDPrepared) Setup() (err error) {
+	op.stmt, err = op.db.Prepare("SELECT id, name, email, created_at FROM customers WHERE id = $1")
+
+	return err
+}
+
+func (op *GetCustomerByIDPrepared) Execute(int) error {
+	op.id = op.IDs.Next()
+	return op.stmt.
+		QueryRow(op.id).
+		Scan(
+			&op.result.ID,
+			&op.result.Name,
+			&op.result.Email,
+			&op.result.CreatedAt,
+		)
+}
+
+func (op *GetCustomerByIDPrepared) Close() error {
+	return closeStmts(op.stmt)
+}
+
+type GetProductByIDPrepared struct {
+	stmt *sql.Stmt
+	GetProductByID
+}
+
+func (op *GetProductByIDPrepared) Name() string {
+	return op.GetProductByID.Name() + " (Prepared)"
+}
+
+func (op *GetProductByIDPrepared) Setup() (err error) {
+	op.stmt, err = op.db.Prepare("SELECT id, name, description, price, stock, created_at FROM products WHERE id = $1")
+
+	return err
+}
+
+func (op *GetProductByIDPrepared) Execute(int) error {
+	var product models.Product
+	return op.stmt.
+		QueryRow(op.IDs.Next()).
+		Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
+		)
+}
+
+func (op *GetProductByIDPrepared) Close() error {
+	return closeStmts(op.stmt)
+}
+
+type UpdateProductByIDPrepared struct {
+	stmt *sql.Stmt
+	UpdateProductByID
+}
+
+func (op *UpdateProductByIDPrepared) Name() string {
+	return op.UpdateProductByID.Name() + " (Prepared)"
+}
+
+func (op *UpdateProductByIDPrepared) Setup() (err error) {
+	op.stmt, err = op.db.Prepare("UPDATE products SET price = $1 WHERE id = $2")
+
+	return err
+}
+
+func (op *UpdateProductByIDPrepared) Execute(int) error {
+	_, err := op.stmt.Exec(op.Price, op.IDs.Next())
+
+	return err
+}
+
+func (op *UpdateProductByIDPrepared) Close() error {
+	return closeStmts(op.stmt)
+}
+
+type DeleteProductByNamePrepared struct {
+	stmt *sql.Stmt
+	DeleteProductByName
+}
+
+func (op *DeleteProductByNamePrepared) Name() string {
+	return op.DeleteProductByName.Name() + " (Prepared)"
+}
+
+func (op *DeleteProductByNamePrepared) Setup() (err error) {
+	op.stmt, err = op.db.Prepare("DELETE FROM products WHERE name = $1")
+
+	return err
+}
+
+func (op *DeleteProductByNamePrepared) Execute(iteration int) error {
+	_, err := op.stmt.Exec(fmt.Sprintf("Product_%d", iteration))
+
+	return err
+}
+
+func (op *DeleteProductByNamePrepared) Close() error {
+	return closeStmts(op.stmt)
+}
+
+type CreateOrderWithProductsByCustomerIDPrepared struct {
+	orderStmt        *sql.Stmt
+	orderProductStmt *sql.Stmt
+	CreateOrderWithProductsByCustomerID
+}
+
+func (op *CreateOrderWithProductsByCustomerIDPrepared) Name() string {
+	return op.CreateOrderWithProductsByCustomerID.Name() + " (Prepared)"
+}
+
+func (op *CreateOrderWithProductsByCustomerIDPrepared) Setup() (err error) {
+	if err := op.CreateOrderWithProductsByCustomerID.Setup(); err != nil {
+		return err
+	}
+
+	op.orderStmt, err = op.db.Prepare("INSERT INTO orders (customer_id, total) VALUES ($1, $2) RETURNING id")
+	if err != nil {
+		return err
+	}
+
+	op.orderProductStmt, err = op.db.Prepare("INSERT INTO order_products (order_id, product_id, quantity, price) VALUES ($1, $2, $3, $4)")
+
+	return err
+}
+
+// Execute binds the prepared statements to the transaction, which reuses them
+// when they are already prepared on the transaction's connection.
+func (op *CreateOrderWithProductsByCustomerIDPrepared) Execute(int) error {
+	tx, err := op.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var orderID uint
+	err = tx.
+		Stmt(op.orderStmt).
+		QueryRow(op.CustomerIDs.Next(), productPrice.Mul(orderQuantity)).
+		Scan(&orderID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.
+		Stmt(op.orderProductStmt).
+		Exec(orderID, op.ProductIDs.Next(), orderQuantity, productPrice)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (op *CreateOrderWithProductsByCustomerIDPrepared) Close() error {
+	return closeStmts(op.orderStmt, op.orderProductStmt)
+}
+
+type GetCustomerStatsByIDPrepared struct {
+	stmt *sql.Stmt
+	GetCustomerStatsByID
+}
+
+func (op *GetCustomerStatsByIDPrepared) Name() string {
+	return op.GetCustomerStatsByID.Name() + " (Prepared)"
+}
+
+func (op *GetCustomerStatsByIDPrepared) Setup() (err error) {
+	op.stmt, err = op.db.Prepare(
+		`SELECT
+            COUNT(*) AS total_orders,
+            COALESCE(SUM(total), 0) AS total_spent
+        FROM orders
+        WHERE customer_id = $1`,
+	)
+
+	return err
+}
+
+func (op *GetCustomerStatsByIDPrepared) Execute(int) error {
+	op.customerID = op.CustomerIDs.Next()
+	return op.stmt.
+		QueryRow(op.customerID).
+		Scan(
+			&op.result.TotalOrders,
+			&op.result.TotalSpent,
+		)
+}
+
+func (op *GetCustomerStatsByIDPrepared) Close() error {
+	return closeStmts(op.stmt)
+}
+
+type GetProductSalesByLimitPrepared struct {
+	stmt *sql.Stmt
+	GetProductSalesByLimit
+}
+
+func (op *GetProductSalesByLimitPrepared) Name() string {
+	return op.GetProductSalesByLimit.Name() + " (Prepared)"
+}
+
+func (op *GetProductSalesByLimitPrepared) Setup() (err error) {
+	op.stmt, err = op.db.Prepare(
+		`SELECT
+            product_id,
+            products.name AS product_name,
+            SUM(quantity) AS total_sales,
+            SUM(order_products.price * quantity) AS revenue
+        FROM order_products
+        JOIN products ON products.id = order_products.product_id
+        GROUP BY product_id, products.name
+        ORDER BY revenue DESC
+        LIMIT $1`,
+	)
+
+	return err
+}
+
+func (op *GetProductSalesByLimitPrepared) Execute(int) error {
+	rows, err := op.stmt.Query(op.Limit)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.results = op.results[:0]
+	for rows.Next() {
+		var r reference.ProductSales
+		if err := rows.Scan(
+			&r.ProductID,
+			&r.ProductName,
+			&r.TotalSales,
+			&r.Revenue,
+		); err != nil {
+			return err
+		}
+		op.results = append(op.results, r)
+	}
+
+	return rows.Err()
+}
+
+func (op *GetProductSalesByLimitPrepared) Close() error {
+	return closeStmts(op.stmt)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// searchLimit is the number of products on the page a search returns.
+const searchLimit = 50
+
+// SearchProducts runs a product search with a random combination of filters in every iteration.
+// It reports how long building the query and running it took on their own.
+type SearchProducts struct {
+	Filters *utils.FilterGenerator
+	filter  utils.ProductFilter
+	ids     []int
+	utils.PhaseTimes
+	SQL
+}
+
+func (op *SearchProducts) Name() string {
+	return "Search Products (Dynamic Filters)"
+}
+
+func (op *SearchProducts) BeforeEach(int) error {
+	op.filter = op.Filters.Next()
+
+	return nil
+}
+
+// build concatenates the conditions of the applied filters, numbering the placeholders as it goes.
+func (op *SearchProducts) build() (string, []any) {
+	var conditions []string
+	var args []any
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	f := op.filter
+	if f.MinPrice != nil {
+		where("price >= $%d", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		where("price <= $%d", *f.MaxPrice)
+	}
+	if f.InStock {
+		conditions = append(conditions, "stock > 0")
+	}
+	if f.Name != "" {
+		where("name ILIKE $%d", "%"+f.Name+"%")
+	}
+	if f.CreatedFrom != nil {
+		where("created_at >= $%d", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		where("created_at < $%d", *f.CreatedTo)
+	}
+
+	var query strings.Builder
+	query.WriteString("SELECT id, name, description, price, stock, created_at FROM products")
+	if len(conditions) > 0 {
+		query.WriteString(" WHERE ")
+		query.WriteString(strings.Join(conditions, " AND "))
+	}
+	args = append(args, searchLimit)
+	fmt.Fprintf(&query, " ORDER BY id LIMIT $%d", len(args))
+
+	return query.String(), args
+}
+
+func (op *SearchProducts) Execute(int) error {
+	start := time.Now()
+	query, args := op.build()
+	op.Add("build", time.Since(start))
+
+	start = time.Now()
+	err := op.run(query, args)
+	op.Add("execute", time.Since(start))
+
+	return err
+}
+
+func (op *SearchProducts) run(query string, args []any) error {
+	rows, err := op.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.ids = op.ids[:0]
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
+		); err != nil {
+			return err
+		}
+		op.ids = append(op.ids, product.ID)
+	}
+
+	return rows.Err()
+}
+
+func (op *SearchProducts) Check(int) error {
+	return reference.CheckProductSearch(context.Background(), op.db, op.filter, searchLimit, op.ids)
+}
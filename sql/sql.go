@@ -5,18 +5,28 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/bootstrap"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/dataset"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/migrations"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
 	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
 )
 
+const (
+	productPrice  models.Money = 9999
+	orderQuantity              = 2
+)
+
 type SQL struct {
 	db *sql.DB
 }
 
 type CreateProduct struct {
+	lastID int
 	SQL
 }
 
@@ -30,15 +40,29 @@ func (op *CreateProduct) Execute(iteration int) error {
 			"INSERT INTO products (name, description, price, stock) VALUES ($1, $2, $3, $4)",
 			fmt.Sprintf("Product_%d", iteration),
 			"Test product",
-			99.99,
+			productPrice,
 			100,
 		)
 
 	return err
 }
 
+func (op *CreateProduct) Setup() error {
+	return op.db.
+		QueryRow("SELECT COALESCE(MAX(id), 0) FROM products").
+		Scan(&op.lastID)
+}
+
+func (op *CreateProduct) Teardown() error {
+	_, err := op.db.Exec("DELETE FROM products WHERE id > $1", op.lastID)
+
+	return err
+}
+
 type GetCustomerByID struct {
-	ID int
+	IDs    utils.Generator
+	id     int
+	result models.Customer
 	SQL
 }
 
@@ -47,23 +71,53 @@ func (op *GetCustomerByID) Name() string {
 }
 
 func (op *GetCustomerByID) Execute(int) error {
-	var customer models.Customer
+	op.id = op.IDs.Next()
 	return op.db.
 		QueryRow(
 			"SELECT id, name, email, created_at FROM customers WHERE id = $1",
-			op.ID,
+			op.id,
+		).
+		Scan(
+			&op.result.ID,
+			&op.result.Name,
+			&op.result.Email,
+			&op.result.CreatedAt,
+		)
+}
+
+func (op *GetCustomerByID) Check(int) error {
+	return reference.CheckCustomer(context.Background(), op.db, op.id, op.result)
+}
+
+type GetProductByID struct {
+	IDs utils.Generator
+	SQL
+}
+
+func (op *GetProductByID) Name() string {
+	return "Get Product by ID"
+}
+
+func (op *GetProductByID) Execute(int) error {
+	var product models.Product
+	return op.db.
+		QueryRow(
+			"SELECT id, name, description, price, stock, created_at FROM products WHERE id = $1",
+			op.IDs.Next(),
 		).
 		Scan(
-			&customer.ID,
-			&customer.Name,
-			&customer.Email,
-			&customer.CreatedAt,
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Stock,
+			&product.CreatedAt,
 		)
 }
 
 type UpdateProductByID struct {
-	ID    int
-	Price float64
+	IDs   utils.Generator
+	Price models.Money
 	SQL
 }
 
@@ -76,7 +130,7 @@ func (op *UpdateProductByID) Execute(int) error {
 		Exec(
 			"UPDATE products SET price = $1 WHERE id = $2",
 			op.Price,
-			op.ID,
+			op.IDs.Next(),
 		)
 
 	return err
@@ -90,6 +144,10 @@ func (op *DeleteProductByName) Name() string {
 	return "Delete Product by Name"
 }
 
+func (op *DeleteProductByName) BeforeEach(iteration int) error {
+	return (&CreateProduct{SQL: op.SQL}).Execute(iteration)
+}
+
 func (op *DeleteProductByName) Execute(iteration int) error {
 	_, err := op.db.Exec(
 		"DELETE FROM products WHERE name = $1",
@@ -100,8 +158,9 @@ func (op *DeleteProductByName) Execute(iteration int) error {
 }
 
 type CreateOrderWithProductsByCustomerID struct {
-	CustomerID int
-	ProductID  int
+	CustomerIDs utils.Generator
+	ProductIDs  utils.Generator
+	lastID      int
 	SQL
 }
 
@@ -120,8 +179,8 @@ func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
 	err = tx.
 		QueryRow(
 			"INSERT INTO orders (customer_id, total) VALUES ($1, $2) RETURNING id",
-			op.CustomerID,
-			199.98,
+			op.CustomerIDs.Next(),
+			productPrice.Mul(orderQuantity),
 		).
 		Scan(&orderID)
 	if err != nil {
@@ -131,9 +190,9 @@ func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
 	_, err = tx.Exec(
 		"INSERT INTO order_products (order_id, product_id, quantity, price) VALUES ($1, $2, $3, $4)",
 		orderID,
-		op.ProductID,
-		2,
-		99.99,
+		op.ProductIDs.Next(),
+		orderQuantity,
+		productPrice,
 	)
 	if err != nil {
 		return err
@@ -142,8 +201,22 @@ func (op *CreateOrderWithProductsByCustomerID) Execute(int) error {
 	return tx.Commit()
 }
 
+func (op *CreateOrderWithProductsByCustomerID) Setup() error {
+	return op.db.
+		QueryRow("SELECT COALESCE(MAX(id), 0) FROM orders").
+		Scan(&op.lastID)
+}
+
+func (op *CreateOrderWithProductsByCustomerID) Teardown() error {
+	_, err := op.db.Exec("DELETE FROM orders WHERE id > $1", op.lastID)
+
+	return err
+}
+
 type GetCustomerStatsByID struct {
-	CustomerID int
+	CustomerIDs utils.Generator
+	customerID  int
+	result      reference.CustomerStats
 	SQL
 }
 
@@ -152,11 +225,7 @@ func (op *GetCustomerStatsByID) Name() string {
 }
 
 func (op *GetCustomerStatsByID) Execute(int) error {
-	var result struct {
-		TotalOrders int64
-		TotalSpent  float64
-	}
-
+	op.customerID = op.CustomerIDs.Next()
 	return op.db.
 		QueryRow(
 			`SELECT 
@@ -164,16 +233,21 @@ func (op *GetCustomerStatsByID) Execute(int) error {
             COALESCE(SUM(total), 0) AS total_spent 
         FROM orders 
         WHERE customer_id = $1`,
-			op.CustomerID,
+			op.customerID,
 		).
 		Scan(
-			&result.TotalOrders,
-			&result.TotalSpent,
+			&op.result.TotalOrders,
+			&op.result.TotalSpent,
 		)
 }
 
+func (op *GetCustomerStatsByID) Check(int) error {
+	return reference.CheckCustomerStats(context.Background(), op.db, op.customerID, op.result)
+}
+
 type GetProductSalesByLimit struct {
-	Limit int
+	Limit   int
+	results []reference.ProductSales
 	SQL
 }
 
@@ -201,13 +275,9 @@ func (op *GetProductSalesByLimit) Execute(int) error {
 	}
 	defer rows.Close()
 
+	op.results = op.results[:0]
 	for rows.Next() {
-		var r struct {
-			ProductID   uint
-			ProductName string
-			TotalSales  int64
-			Revenue     float64
-		}
+		var r reference.ProductSales
 		if err := rows.Scan(
 			&r.ProductID,
 			&r.ProductName,
@@ -216,14 +286,44 @@ func (op *GetProductSalesByLimit) Execute(int) error {
 		); err != nil {
 			return err
 		}
+		op.results = append(op.results, r)
 	}
 
 	return rows.Err()
 }
 
+func (op *GetProductSalesByLimit) Check(int) error {
+	return reference.CheckProductSales(context.Background(), op.db, op.Limit, op.results)
+}
+
+func selectIDs(db *sql.DB, query string) ([]int, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
 func main() {
-	dsn := "host=localhost user= password= dbname= port=5432 sslmode=disable"
-	db, err := sql.Open("postgres", dsn)
+	config := utils.ParseConfig()
+
+	schema, err := bootstrap.Start(context.Background(), config)
+	if err != nil {
+		log.Fatalf("failed to prepare database: %v", err)
+	}
+
+	db, err := config.OpenDB(schema.DSN)
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
@@ -235,64 +335,261 @@ func main() {
 		log.Fatalf("failed to ping database: %v", err)
 	}
 
-	var customer models.Customer
-	if err = db.
-		QueryRow("SELECT id, name, email, created_at FROM customers LIMIT 1").
-		Scan(
-			&customer.ID,
-			&customer.Name,
-			&customer.Email,
-			&customer.CreatedAt,
-		); err != nil {
-		log.Fatalf("failed to get customer: %v", err)
+	if err := migrations.Check(ctx, db); err != nil {
+		log.Fatalf("failed to check schema version: %v", err)
 	}
 
-	var product models.Product
-	if err = db.
-		QueryRow("SELECT id, name, description, price, stock, created_at FROM products LIMIT 1").
-		Scan(
-			&product.ID,
-			&product.Name,
-			&product.Description,
-			&product.Price,
-			&product.Stock,
-			&product.CreatedAt,
-		); err != nil {
-		log.Fatalf("failed to get product: %v", err)
+	metadata, err := dataset.Describe(ctx, db)
+	if err != nil {
+		log.Fatalf("failed to describe dataset: %v", err)
+	}
+
+	customerIDs, err := selectIDs(db, "SELECT id FROM customers ORDER BY id")
+	if err != nil {
+		log.Fatalf("failed to get customers: %v", err)
+	}
+
+	productIDs, err := selectIDs(db, "SELECT id FROM products ORDER BY id")
+	if err != nil {
+		log.Fatalf("failed to get products: %v", err)
 	}
 
-	iterations := 10000
+	generator := func(ids []int, stream uint64) utils.Generator {
+		g, err := config.Generator(ids, stream)
+		if err != nil {
+			log.Fatalf("failed to create generator: %v", err)
+		}
+
+		return g
+	}
 
 	operations := []utils.Operation{
 		&CreateProduct{
 			SQL: SQL{db},
 		},
 		&GetCustomerByID{
-			ID:  customer.ID,
+			IDs: generator(customerIDs, 0),
 			SQL: SQL{db},
 		},
+		&GetProductByID{
+			IDs: generator(productIDs, 1),
+			SQL: SQL{db},
+		},
+		&GetProductByIDFloat64{
+			GetProductByID: GetProductByID{
+				IDs: generator(productIDs, 1),
+				SQL: SQL{db},
+			},
+		},
+		&GetProductByIDNullString{
+			GetProductByID: GetProductByID{
+				IDs: generator(productIDs, 1),
+				SQL: SQL{db},
+			},
+		},
+		&GetProductByIDCoalesce{
+			GetProductByID: GetProductByID{
+				IDs: generator(productIDs, 1),
+				SQL: SQL{db},
+			},
+		},
 		&UpdateProductByID{
-			ID:    product.ID,
+			IDs:   generator(productIDs, 1),
+			Price: 8999,
+			SQL:   SQL{db},
+		},
+		&UpdateProductByIDFloat64{
+			IDs:   generator(productIDs, 1),
 			Price: 89.99,
 			SQL:   SQL{db},
 		},
+		&UpdateProductByExpression{
+			IDs: generator(productIDs, 1),
+			productUpdate: productUpdate{
+				SQL: SQL{db},
+			},
+		},
+		&UpdateProductsByIDs{
+			IDs: generator(productIDs, 1),
+			productUpdate: productUpdate{
+				SQL: SQL{db},
+			},
+		},
+		&UpdateProductsByPriceBand{
+			productUpdate: productUpdate{
+				SQL: SQL{db},
+			},
+		},
 		&CreateOrderWithProductsByCustomerID{
-			CustomerID: customer.ID,
-			ProductID:  product.ID,
-			SQL:        SQL{db},
+			CustomerIDs: generator(customerIDs, 0),
+			ProductIDs:  generator(productIDs, 1),
+			SQL:         SQL{db},
+		},
+		&CreateOrderWithProductsByCustomerIDFloat64{
+			CreateOrderWithProductsByCustomerID: CreateOrderWithProductsByCustomerID{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				SQL:         SQL{db},
+			},
 		},
 		&GetCustomerStatsByID{
-			CustomerID: customer.ID,
-			SQL:        SQL{db},
+			CustomerIDs: generator(customerIDs, 0),
+			SQL:         SQL{db},
+		},
+		&GetCustomerStatsByIDFloat64{
+			GetCustomerStatsByID: GetCustomerStatsByID{
+				CustomerIDs: generator(customerIDs, 0),
+				SQL:         SQL{db},
+			},
 		},
 		&GetProductSalesByLimit{
 			Limit: 10,
 			SQL:   SQL{db},
 		},
+		&GetProductSalesByLimitFloat64{
+			GetProductSalesByLimit: GetProductSalesByLimit{
+				Limit: 10,
+				SQL:   SQL{db},
+			},
+		},
+		&LoadCustomerGraphNPlusOne{
+			customerGraph: customerGraph{
+				CustomerIDs: generator(customerIDs, 0),
+				SQL:         SQL{db},
+			},
+		},
+		&LoadCustomerGraphJoin{
+			customerGraph: customerGraph{
+				CustomerIDs: generator(customerIDs, 0),
+				SQL:         SQL{db},
+			},
+		},
+		&PaginateProductsOffset{
+			productWalk: productWalk{
+				SQL: SQL{db},
+			},
+		},
+		&PaginateProductsKeyset{
+			productWalk: productWalk{
+				SQL: SQL{db},
+			},
+		},
+		&SearchProducts{
+			Filters: config.FilterGenerator(2),
+			SQL:     SQL{db},
+		},
+		&StreamOrderProducts{
+			OrderProductStream: reference.OrderProductStream{Limit: config.StreamRows},
+			SQL:                SQL{db},
+		},
+		&ReserveInventory{
+			CustomerIDs: generator(customerIDs, 0),
+			ProductIDs:  generator(productIDs, 1),
+			Workers:     config.Workers,
+			SQL:         SQL{db},
+		},
+		&ReserveInventoryUnordered{
+			ReserveInventory: ReserveInventory{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				Workers:     config.Workers,
+				SQL:         SQL{db},
+			},
+		},
+		&UpsertCustomerByEmail{
+			CustomerIDs:   generator(customerIDs, 0),
+			ConflictRatio: 0.1,
+			SQL:           SQL{db},
+		},
+		&UpsertCustomerByEmailHighConflict{
+			UpsertCustomerByEmail: UpsertCustomerByEmail{
+				CustomerIDs:   generator(customerIDs, 0),
+				ConflictRatio: 0.9,
+				SQL:           SQL{db},
+			},
+		},
 		&DeleteProductByName{
 			SQL: SQL{db},
 		},
+		&DeleteCustomerWithOrdersCascade{
+			customerDelete: customerDelete{
+				SQL: SQL{db},
+			},
+		},
+		&DeleteCustomerWithOrdersChildrenFirst{
+			customerDelete: customerDelete{
+				SQL: SQL{db},
+			},
+		},
+		&CreateProductsInBulk{
+			BatchSize: config.BatchSize,
+			CreateProduct: CreateProduct{
+				SQL: SQL{db},
+			},
+		},
+		&CreateProductPrepared{
+			CreateProduct: CreateProduct{
+				SQL: SQL{db},
+			},
+		},
+		&GetCustomerByIDPrepared{
+			GetCustomerByID: GetCustomerByID{
+				IDs: generator(customerIDs, 0),
+				SQL: SQL{db},
+			},
+		},
+		&GetProductByIDPrepared{
+			GetProductByID: GetProductByID{
+				IDs: generator(productIDs, 1),
+				SQL: SQL{db},
+			},
+		},
+		&UpdateProductByIDPrepared{
+			UpdateProductByID: UpdateProductByID{
+				IDs:   generator(productIDs, 1),
+				Price: 8999,
+				SQL:   SQL{db},
+			},
+		},
+		&CreateOrderWithProductsByCustomerIDPrepared{
+			CreateOrderWithProductsByCustomerID: CreateOrderWithProductsByCustomerID{
+				CustomerIDs: generator(customerIDs, 0),
+				ProductIDs:  generator(productIDs, 1),
+				SQL:         SQL{db},
+			},
+		},
+		&GetCustomerStatsByIDPrepared{
+			GetCustomerStatsByID: GetCustomerStatsByID{
+				CustomerIDs: generator(customerIDs, 0),
+				SQL:         SQL{db},
+			},
+		},
+		&GetProductSalesByLimitPrepared{
+			GetProductSalesByLimit: GetProductSalesByLimit{
+				Limit: 10,
+				SQL:   SQL{db},
+			},
+		},
+		&DeleteProductByNamePrepared{
+			DeleteProductByName: DeleteProductByName{
+				SQL: SQL{db},
+			},
+		},
 	}
 
-	utils.PrintResult(operations, iterations)
+	// COPY FROM STDIN through database/sql is a feature of lib/pq.
+	if config.Driver == "pq" {
+		operations = append(operations, &CreateProductsInBulkCopy{
+			CreateProductsInBulk: CreateProductsInBulk{
+				BatchSize: config.BatchSize,
+				CreateProduct: CreateProduct{
+					SQL: SQL{db},
+				},
+			},
+		})
+	}
+
+	if err := config.Run(operations, slices.Concat(config.Labels(), schema.Labels(), metadata.Labels())...); err != nil {
+		log.Fatalf("failed to run operations: %v", err)
+	}
 }
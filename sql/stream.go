@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+)
+
+// StreamOrderProducts scans the order lines one row at a time into the same line,
+// so nothing but the current row is held in memory.
+type StreamOrderProducts struct {
+	reference.OrderProductStream
+	SQL
+}
+
+func (op *StreamOrderProducts) Name() string {
+	return "Stream Order Products (Rows)"
+}
+
+func (op *StreamOrderProducts) Setup() error {
+	return op.db.
+		QueryRow(
+			"SELECT COUNT(*), COALESCE(MAX(id), 0) FROM (SELECT id FROM order_products ORDER BY id LIMIT $1) lines",
+			op.Limit,
+		).
+		Scan(&op.N, &op.LastID)
+}
+
+func (op *StreamOrderProducts) Execute(int) error {
+	rows, err := op.db.Query(
+		"SELECT id, order_id, product_id, quantity, price FROM order_products WHERE id <= $1 ORDER BY id",
+		op.LastID,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.Reset()
+
+	var line models.OrderProduct
+	for rows.Next() {
+		if err := rows.Scan(&line.ID, &line.OrderID, &line.ProductID, &line.Quantity, &line.Price); err != nil {
+			return err
+		}
+		op.Add(line.Price, line.Quantity)
+	}
+
+	return rows.Err()
+}
+
+func (op *StreamOrderProducts) Check(int) error {
+	return op.OrderProductStream.Check(context.Background(), op.db)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// productUpdate is embedded by the SQL update operations, which differ only in the WHERE clause of their statement.
+type productUpdate struct {
+	products map[int]models.Product
+	ids      []int
+	affected int
+	SQL
+}
+
+func (u *productUpdate) Setup() error {
+	rows, err := u.db.Query("SELECT id, price, stock FROM products")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	u.products = make(map[int]models.Product)
+	for rows.Next() {
+		var p models.Product
+		if err := rows.Scan(&p.ID, &p.Price, &p.Stock); err != nil {
+			return err
+		}
+		u.products[p.ID] = p
+	}
+
+	return rows.Err()
+}
+
+// Rows returns the rows the last execution's statement reported as affected.
+func (u *productUpdate) Rows() int {
+	return u.affected
+}
+
+func (u *productUpdate) record(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	u.affected = int(affected)
+
+	return err
+}
+
+func (u *productUpdate) Check(int) error {
+	return reference.CheckProductsRepriced(context.Background(), u.db, u.products, u.ids)
+}
+
+func (u *productUpdate) AfterEach(int) error {
+	prices := make([]string, len(u.ids))
+	stocks := make([]int, len(u.ids))
+	for i, id := range u.ids {
+		prices[i] = u.products[id].Price.String()
+		stocks[i] = u.products[id].Stock
+	}
+
+	_, err := u.db.Exec(
+		`UPDATE products p SET price = s.price, stock = s.stock
+        FROM unnest($1::int[], $2::numeric[], $3::int[]) AS s(id, price, stock)
+        WHERE p.id = s.id`,
+		pq.Array(u.ids),
+		pq.Array(prices),
+		pq.Array(stocks),
+	)
+
+	return err
+}
+
+type UpdateProductByExpression struct {
+	IDs utils.Generator
+	productUpdate
+}
+
+func (op *UpdateProductByExpression) Name() string {
+	return "Update Product by Expression"
+}
+
+func (op *UpdateProductByExpression) BeforeEach(int) error {
+	op.ids = []int{op.IDs.Next()}
+
+	return nil
+}
+
+func (op *UpdateProductByExpression) Execute(int) error {
+	return op.record(op.db.Exec(
+		"UPDATE products SET price = price * 1.1, stock = stock + $1 WHERE id = $2",
+		reference.Restock,
+		op.ids[0],
+	))
+}
+
+type UpdateProductsByIDs struct {
+	IDs utils.Generator
+	productUpdate
+}
+
+func (op *UpdateProductsByIDs) Name() string {
+	return "Update Products by Expression (IN)"
+}
+
+func (op *UpdateProductsByIDs) BeforeEach(int) error {
+	var err error
+	op.ids, err = reference.NextUpdateBatch(op.IDs)
+
+	return err
+}
+
+func (op *UpdateProductsByIDs) Execute(int) error {
+	return op.record(op.db.Exec(
+		"UPDATE products SET price = price * 1.1, stock = stock + $1 WHERE id = ANY($2)",
+		reference.Restock,
+		pq.Array(op.ids),
+	))
+}
+
+// UpdateProductsByPriceBand updates every product cheaper than CheapPrice, the same products in every
+// iteration since they are restored in between.
+type UpdateProductsByPriceBand struct {
+	productUpdate
+}
+
+func (op *UpdateProductsByPriceBand) Name() string {
+	return "Update Products by Expression (Price Band)"
+}
+
+func (op *UpdateProductsByPriceBand) Setup() error {
+	if err := op.productUpdate.Setup(); err != nil {
+		return err
+	}
+
+	for id, p := range op.products {
+		if p.Price < reference.CheapPrice {
+			op.ids = append(op.ids, id)
+		}
+	}
+
+	return nil
+}
+
+func (op *UpdateProductsByPriceBand) Execute(int) error {
+	return op.record(op.db.Exec(
+		"UPDATE products SET price = price * 1.1, stock = stock + $1 WHERE price < $2",
+		reference.Restock,
+		reference.CheapPrice,
+	))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/reference"
+	"github.com/yahn1ukov/go-orm-sql-efficiency/utils"
+)
+
+// UpsertCustomerByEmail inserts a customer with ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name,
+// so the unique email index turns the insert into a rename of the customer that already has the email.
+// ConflictRatio is the share of iterations that reuse an existing email. Teardown restores the names
+// and deletes the inserted customers.
+type UpsertCustomerByEmail struct {
+	CustomerIDs   utils.Generator
+	ConflictRatio float64
+	customers     map[int]models.Customer
+	renamed       map[int]bool
+	email         string
+	name          string
+	lastID        int
+	SQL
+}
+
+func (op *UpsertCustomerByEmail) Name() string {
+	return "Upsert Customer by Email"
+}
+
+func (op *UpsertCustomerByEmail) Setup() error {
+	rows, err := op.db.Query("SELECT id, name, email FROM customers")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	op.customers = make(map[int]models.Customer)
+	op.renamed = make(map[int]bool)
+	for rows.Next() {
+		var c models.Customer
+		if err := rows.Scan(&c.ID, &c.Name, &c.Email); err != nil {
+			return err
+		}
+		op.customers[c.ID] = c
+		op.lastID = max(op.lastID, c.ID)
+	}
+
+	return rows.Err()
+}
+
+func (op *UpsertCustomerByEmail) BeforeEach(iteration int) error {
+	op.name = fmt.Sprintf("Customer_%d", iteration)
+	op.email = fmt.Sprintf("customer_%d@upsert.test", iteration)
+
+	if float64(iteration%100) < op.ConflictRatio*100 {
+		id := op.CustomerIDs.Next()
+		op.email = op.customers[id].Email
+		op.renamed[id] = true
+	}
+
+	return nil
+}
+
+func (op *UpsertCustomerByEmail) Execute(int) error {
+	_, err := op.db.
+		Exec(
+			"INSERT INTO customers (name, email) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name",
+			op.name,
+			op.email,
+		)
+
+	return err
+}
+
+func (op *UpsertCustomerByEmail) Check(int) error {
+	return reference.CheckCustomerByEmail(context.Background(), op.db, op.email, op.name)
+}
+
+func (op *UpsertCustomerByEmail) Teardown() error {
+	var errs []error
+	for id := range op.renamed {
+		_, err := op.db.Exec("UPDATE customers SET name = $1 WHERE id = $2", op.customers[id].Name, id)
+		errs = append(errs, err)
+	}
+
+	_, err := op.db.Exec("DELETE FROM customers WHERE id > $1", op.lastID)
+
+	return errors.Join(append(errs, err)...)
+}
+
+type UpsertCustomerByEmailHighConflict struct {
+	UpsertCustomerByEmail
+}
+
+func (op *UpsertCustomerByEmailHighConflict) Name() string {
+	return op.UpsertCustomerByEmail.Name() + " (High Conflict)"
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 )
 
+const checkInterval = 100
+
 type Result struct {
 	Operation  string
 	AvgLatency float64
@@ -16,6 +18,32 @@ type Result struct {
 	Throughput float64
 	AvgRAM     float64
 	GCPause    float64
+	Checks     int
+	Mismatches int
+	// RoundTrips is the average number of queries per iteration, when the operation counts them.
+	RoundTrips      float64
+	CountRoundTrips bool
+	// RowsPerSecond and AllocsPerRow relate the rows written or read to the time and heap allocations
+	// they took, when the operation reports them. PeakHeap is the most heap in MB held above the heap
+	// before the first iteration, sampled while such an operation runs.
+	RowsPerSecond float64
+	AllocsPerRow  float64
+	PeakHeap      float64
+	CountRows     bool
+	// Breakdown holds what the operation measured about parts of its executions.
+	Breakdown []Label
+}
+
+// Status reports whether the sampled results of the operation matched the reference.
+func (r Result) Status() string {
+	switch {
+	case r.Checks == 0:
+		return "unchecked"
+	case r.Mismatches == 0:
+		return "valid"
+	default:
+		return fmt.Sprintf("INVALID (%d/%d)", r.Mismatches, r.Checks)
+	}
 }
 
 type Operation interface {
@@ -23,15 +51,134 @@ type Operation interface {
 	Execute(int) error
 }
 
-func Run(op Operation, iterations int) Result {
+// Variant reports an operation under its name followed by Suffix,
+// for running the same operation on differently configured connections.
+type Variant struct {
+	Operation
+	Suffix string
+}
+
+func (v Variant) Name() string {
+	return v.Operation.Name() + " (" + v.Suffix + ")"
+}
+
+// WithSetup is implemented by operations that prepare fixtures once before they are measured.
+type WithSetup interface {
+	Setup() error
+}
+
+// WithBeforeEach is implemented by operations that prepare every iteration outside the timed section.
+type WithBeforeEach interface {
+	BeforeEach(int) error
+}
+
+// WithAfterEach is implemented by operations that clean up every iteration outside the timed section.
+type WithAfterEach interface {
+	AfterEach(int) error
+}
+
+// WithCheck is implemented by operations that can verify the result of their last execution.
+// It runs on every checkInterval-th iteration outside the timed section.
+type WithCheck interface {
+	Check(int) error
+}
+
+// WithRoundTrips is implemented by operations that count the queries they send to the database.
+// RoundTrips returns a running total, only its growth during Execute is attributed to the operation.
+type WithRoundTrips interface {
+	RoundTrips() int64
+}
+
+// WithRows is implemented by operations that write or read many rows per execution.
+// Rows returns the number of rows written or read by every execution.
+type WithRows interface {
+	Rows() int
+}
+
+// WithBreakdown is implemented by operations that measure parts of their executions themselves.
+// Breakdown returns the labelled measurements after the last iteration, they are printed below the result.
+type WithBreakdown interface {
+	Breakdown() []Label
+}
+
+// WithTeardown is implemented by operations that clean up once after they are measured.
+type WithTeardown interface {
+	Teardown() error
+}
+
+// WithClose is implemented by operations that hold resources such as prepared statements. Close releases them
+// after the operation is measured, also when -teardown=false keeps the rows it wrote.
+type WithClose interface {
+	Close() error
+}
+
+func Run(op Operation, config Config) Result {
+	iterations := config.Iterations
+
+	// The hooks of a variant are the hooks of the operation it wraps.
+	hooks := op
+	if v, ok := op.(Variant); ok {
+		hooks = v.Operation
+	}
+
+	if setup, ok := hooks.(WithSetup); ok {
+		if err := setup.Setup(); err != nil {
+			log.Fatalf("failed to set up operation \"%s\": %v", op.Name(), err)
+		}
+	}
+
+	beforeEach, hasBeforeEach := hooks.(WithBeforeEach)
+	afterEach, hasAfterEach := hooks.(WithAfterEach)
+	check, hasCheck := hooks.(WithCheck)
+	counter, hasCounter := hooks.(WithRoundTrips)
+	rows, hasRows := hooks.(WithRows)
+
 	times := make([]time.Duration, iterations)
 	var memStart, memEnd runtime.MemStats
 
+	// Allocations made by the per-iteration hooks are measured separately and excluded from the result.
+	var hookAlloc, hookMallocs uint64
+	measure := func(fn func()) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+
+		fn()
+
+		runtime.ReadMemStats(&after)
+		hookAlloc += after.TotalAlloc - before.TotalAlloc
+		hookMallocs += after.Mallocs - before.Mallocs
+	}
+	hook := func(name string, fn func(int) error, iteration int) {
+		measure(func() {
+			if err := fn(iteration); err != nil {
+				log.Fatalf("failed to run %s of operation \"%s\": %v", name, op.Name(), err)
+			}
+		})
+	}
+
+	var checks, mismatches int
+	var roundTrips int64
+
 	runtime.GC()
 	runtime.ReadMemStats(&memStart)
 	gcPauseStart := memStart.PauseTotalNs
 
+	var heap *heapSampler
+	baseHeap := heapObjects()
+	if hasRows {
+		heap = sampleHeap()
+	}
+
 	for i := 0; i < iterations; i++ {
+		if hasBeforeEach {
+			hook("before each", beforeEach.BeforeEach, i)
+		}
+
+		var queries int64
+		if hasCounter {
+			queries = counter.RoundTrips()
+		}
+
 		start := time.Now()
 
 		if err := op.Execute(i); err != nil {
@@ -39,11 +186,51 @@ func Run(op Operation, iterations int) Result {
 		}
 
 		times[i] = time.Since(start)
+
+		if hasCounter {
+			roundTrips += counter.RoundTrips() - queries
+		}
+
+		if hasCheck && i%checkInterval == 0 {
+			measure(func() {
+				checks++
+				if err := check.Check(i); err != nil {
+					mismatches++
+					log.Printf("operation \"%s\" returned an invalid result in iteration %d: %v", op.Name(), i, err)
+				}
+			})
+		}
+
+		if hasAfterEach {
+			hook("after each", afterEach.AfterEach, i)
+		}
+	}
+
+	var peakHeap uint64
+	if hasRows {
+		peakHeap = heap.Stop()
 	}
 
 	runtime.ReadMemStats(&memEnd)
 	gcPauseEnd := memEnd.PauseTotalNs
 
+	var breakdown []Label
+	if b, ok := hooks.(WithBreakdown); ok {
+		breakdown = b.Breakdown()
+	}
+
+	if teardown, ok := hooks.(WithTeardown); ok && config.Teardown {
+		if err := teardown.Teardown(); err != nil {
+			log.Fatalf("failed to tear down operation \"%s\": %v", op.Name(), err)
+		}
+	}
+
+	if closer, ok := hooks.(WithClose); ok {
+		if err := closer.Close(); err != nil {
+			log.Fatalf("failed to close operation \"%s\": %v", op.Name(), err)
+		}
+	}
+
 	var totalTime time.Duration
 	for _, t := range times {
 		totalTime += t
@@ -57,10 +244,18 @@ func Run(op Operation, iterations int) Result {
 
 	throughput := float64(iterations) / totalTime.Seconds()
 
-	avgRAM := float64(memEnd.TotalAlloc-memStart.TotalAlloc) / float64(iterations) / (1024 * 1024)
+	avgRAM := float64(memEnd.TotalAlloc-memStart.TotalAlloc-hookAlloc) / float64(iterations) / (1024 * 1024)
 
 	gcPause := float64(gcPauseEnd-gcPauseStart) / 1e6
 
+	var rowsPerSecond, allocsPerRow, peakHeapMB float64
+	if hasRows {
+		total := float64(rows.Rows()) * float64(iterations)
+		rowsPerSecond = total / totalTime.Seconds()
+		allocsPerRow = float64(memEnd.Mallocs-memStart.Mallocs-hookMallocs) / total
+		peakHeapMB = float64(peakHeap-min(peakHeap, baseHeap)) / (1024 * 1024)
+	}
+
 	return Result{
 		Operation:  op.Name(),
 		AvgLatency: avgLatency,
@@ -68,24 +263,69 @@ func Run(op Operation, iterations int) Result {
 		Throughput: throughput,
 		AvgRAM:     avgRAM,
 		GCPause:    gcPause,
+		Checks:     checks,
+		Mismatches: mismatches,
+
+		RoundTrips:      float64(roundTrips) / float64(iterations),
+		CountRoundTrips: hasCounter,
+
+		RowsPerSecond: rowsPerSecond,
+		AllocsPerRow:  allocsPerRow,
+		PeakHeap:      peakHeapMB,
+		CountRows:     hasRows,
+
+		Breakdown: breakdown,
 	}
 }
 
-func PrintResult(operations []Operation, iterations int) {
+type Label struct {
+	Name  string
+	Value string
+}
+
+func PrintLabels(labels ...Label) {
+	for _, label := range labels {
+		fmt.Printf("%-20s %s\n", label.Name+":", label.Value)
+	}
+	fmt.Println()
+}
+
+func PrintResult(operations []Operation, config Config) {
 	fmt.Printf(
-		"%-60s %-20s %-20s %-20s %-20s %-20s\n",
-		"Operation", "Avg Latency (ms)", "P95 Latency (ms)", "Throughput (ops/s)", "Avg RAM (MB)", "GC Pause (ms)",
+		"%-60s %-20s %-20s %-20s %-20s %-20s %-20s %-20s %-20s %-20s %s\n",
+		"Operation", "Avg Latency (ms)", "P95 Latency (ms)", "Throughput (ops/s)", "Avg RAM (MB)", "GC Pause (ms)", "Round Trips", "Throughput (rows/s)", "Allocs/Row", "Peak Heap (MB)", "Result",
 	)
 	for _, operation := range operations {
-		result := Run(operation, iterations)
+		result := Run(operation, config)
+
+		roundTrips := "-"
+		if result.CountRoundTrips {
+			roundTrips = fmt.Sprintf("%.2f", result.RoundTrips)
+		}
+
+		rowsPerSecond, allocsPerRow, peakHeap := "-", "-", "-"
+		if result.CountRows {
+			rowsPerSecond = fmt.Sprintf("%.4f", result.RowsPerSecond)
+			allocsPerRow = fmt.Sprintf("%.2f", result.AllocsPerRow)
+			peakHeap = fmt.Sprintf("%.4f", result.PeakHeap)
+		}
+
 		fmt.Printf(
-			"%-60s %-20.4f %-20.4f %-20.4f %-20.4f %-20.4f\n",
+			"%-60s %-20.4f %-20.4f %-20.4f %-20.4f %-20.4f %-20s %-20s %-20s %-20s %s\n",
 			result.Operation,
 			result.AvgLatency,
 			result.P95Latency,
 			result.Throughput,
 			result.AvgRAM,
 			result.GCPause,
+			roundTrips,
+			rowsPerSecond,
+			allocsPerRow,
+			peakHeap,
+			result.Status(),
 		)
+		for _, label := range result.Breakdown {
+			fmt.Printf("  %-58s %s\n", label.Name, label.Value)
+		}
 	}
 }
@@ -0,0 +1,6 @@
+package utils
+
+// BulkProducts is the number of products every execution of the bulk operations imports. The operations
+// delete the products of the previous iteration outside the timed section, so the table keeps its size
+// however many iterations run, and the rows of the last one stay until the teardown.
+const BulkProducts = 1000
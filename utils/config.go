@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const DefaultDSN = "host=localhost user= password= dbname= port=5432 sslmode=disable"
+
+// MaxBatchSize is the most products a bulk statement can insert: every row binds 4 parameters
+// and PostgreSQL accepts at most 65535 per statement.
+const MaxBatchSize = 65535 / 4
+
+type Config struct {
+	DSN    string
+	Driver string
+	// DriverLabel is reported instead of Driver by implementations whose operations do not run on the
+	// database/sql driver. Driver must stay a -driver value, the bootstrap still opens connections with it.
+	DriverLabel  string
+	Isolation    string
+	Schema       string
+	Results      string
+	Distribution string
+	Seed         uint64
+	Operations   string
+	Iterations   int
+	BatchSize    int
+	Workers      int
+	StreamRows   int
+	Teardown     bool
+	List         bool
+}
+
+func ParseConfig() Config {
+	var config Config
+
+	flag.StringVar(&config.DSN, "dsn", DefaultDSN, "PostgreSQL connection string")
+	flag.StringVar(&config.Driver, "driver", "pq", "database/sql driver under every implementation: pq or pgx")
+	flag.StringVar(&config.Isolation, "isolation", "snapshot", "database isolation strategy applied before the run: none, reseed, snapshot or template")
+	flag.StringVar(&config.Schema, "schema", "migrations", "source of the benchmarked tables: migrations, ent or gorm")
+	flag.StringVar(&config.Results, "results", "results", "directory the run artifacts are written to")
+	flag.StringVar(&config.Distribution, "distribution", "uniform", "parameter distribution: sequential, uniform, zipf or hotspot")
+	flag.Uint64Var(&config.Seed, "seed", 1, "seed of the parameter generators")
+	flag.StringVar(&config.Operations, "operations", "", "regular expression selecting the operations to run by name")
+	flag.IntVar(&config.Iterations, "iterations", 10000, "iterations per operation")
+	flag.IntVar(&config.BatchSize, "batch-size", 100, "rows per statement of the bulk operations")
+	flag.IntVar(&config.Workers, "workers", 8, "concurrent workers of the contended operations")
+	flag.IntVar(&config.StreamRows, "stream-rows", 10000, "order_products rows read by every execution of the streaming operations")
+	flag.BoolVar(&config.Teardown, "teardown", true, "undo the rows written by the operations after they are measured")
+	flag.BoolVar(&config.List, "list", false, "list the selected operations without running them or writing to the database")
+	flag.Parse()
+
+	return config
+}
+
+func (c Config) Generator(values []int, stream uint64) (Generator, error) {
+	return NewGenerator(c.Distribution, values, c.Seed+stream)
+}
+
+func (c Config) Labels() []Label {
+	driver := c.Driver
+	if c.DriverLabel != "" {
+		driver = c.DriverLabel
+	}
+
+	return []Label{
+		{Name: "Driver", Value: driver},
+		{Name: "Isolation", Value: c.Isolation},
+		{Name: "Distribution", Value: fmt.Sprintf("%s (seed %d)", c.Distribution, c.Seed)},
+		{Name: "Batch Size", Value: strconv.Itoa(c.BatchSize)},
+		{Name: "Workers", Value: strconv.Itoa(c.Workers)},
+		{Name: "Stream Rows", Value: strconv.Itoa(c.StreamRows)},
+	}
+}
+
+// Validate rejects flag values no operation can run with.
+func (c Config) Validate() error {
+	if c.Iterations < 1 {
+		return fmt.Errorf("invalid iterations %d", c.Iterations)
+	}
+	if c.BatchSize < 1 || c.BatchSize > MaxBatchSize {
+		return fmt.Errorf("invalid batch size %d, it must be between 1 and %d", c.BatchSize, MaxBatchSize)
+	}
+	if c.Workers < 1 {
+		return fmt.Errorf("invalid workers %d", c.Workers)
+	}
+	if c.StreamRows < 1 {
+		return fmt.Errorf("invalid stream rows %d", c.StreamRows)
+	}
+
+	return nil
+}
+
+// Run benchmarks the operations selected by -operations and prints the labels above the results.
+// With -list it only prints the names of the selected operations, one per line.
+func (c Config) Run(operations []Operation, labels ...Label) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	pattern, err := regexp.Compile(c.Operations)
+	if err != nil {
+		return fmt.Errorf("invalid operations pattern: %w", err)
+	}
+
+	var selected []Operation
+	for _, op := range operations {
+		if pattern.MatchString(op.Name()) {
+			selected = append(selected, op)
+		}
+	}
+
+	if c.List {
+		for _, op := range selected {
+			fmt.Println(op.Name())
+		}
+
+		return nil
+	}
+
+	PrintLabels(labels...)
+	PrintResult(selected, c)
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestConfigValidateBatchSize(t *testing.T) {
+	valid := Config{Iterations: 1, BatchSize: 100, Workers: 1, StreamRows: 1}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate(%+v) failed: %v", valid, err)
+	}
+
+	for batchSize, ok := range map[int]bool{
+		0:                false,
+		1:                true,
+		MaxBatchSize:     true,
+		MaxBatchSize + 1: false,
+		65535:            false,
+	} {
+		config := valid
+		config.BatchSize = batchSize
+		if err := config.Validate(); (err == nil) != ok {
+			t.Errorf("Validate with -batch-size=%d returned %v", batchSize, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const deadlockDetected = "40P01"
+
+// lockWaitThreshold separates locks that waited for another transaction from uncontended ones. A blocked
+// lock waits until the holder commits after several more statements, while an uncontended lock of a few
+// rows returns in a fraction of it on a local database.
+const lockWaitThreshold = 2 * time.Millisecond
+
+// Parallel runs fn once for every worker concurrently and returns the errors of all of them.
+func Parallel(workers int, fn func(worker int) error) error {
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[w] = fn(w)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Contention counts what concurrent reservation transactions ran into. Operations embedding it
+// report the counts below their result.
+type Contention struct {
+	reservations atomic.Int64
+	rejections   atomic.Int64
+	lockWaits    atomic.Int64
+	lockWait     atomic.Int64
+	deadlocks    atomic.Int64
+}
+
+// Lock runs a statement that blocks until it holds its row locks and times it. Statements that took at least
+// lockWaitThreshold are counted as lock waits, and their time adds up to the total lock wait.
+func (c *Contention) Lock(statement func() error) error {
+	start := time.Now()
+	err := statement()
+	if elapsed := time.Since(start); elapsed >= lockWaitThreshold {
+		c.lockWaits.Add(1)
+		c.lockWait.Add(int64(elapsed))
+	}
+
+	return err
+}
+
+// Reserve runs a reservation transaction until it completes. A transaction aborted by a deadlock is counted
+// and runs again. reserved reports whether the transaction could reserve what it asked for.
+func (c *Contention) Reserve(tx func() (reserved bool, err error)) error {
+	for {
+		reserved, err := tx()
+		if SQLState(err) == deadlockDetected {
+			c.deadlocks.Add(1)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		c.reservations.Add(1)
+		if !reserved {
+			c.rejections.Add(1)
+		}
+
+		return nil
+	}
+}
+
+func (c *Contention) Breakdown() []Label {
+	reservations := c.reservations.Load()
+	rate := func(n int64) string {
+		return fmt.Sprintf("%d (%.4f per reservation)", n, float64(n)/float64(max(reservations, 1)))
+	}
+
+	return []Label{
+		{Name: "reservations", Value: fmt.Sprintf("%d", reservations)},
+		{Name: "rejected", Value: rate(c.rejections.Load())},
+		{Name: "lock waits", Value: rate(c.lockWaits.Load())},
+		{Name: "lock wait time", Value: time.Duration(c.lockWait.Load()).String()},
+		{Name: "deadlocks", Value: rate(c.deadlocks.Load())},
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestContentionLock(t *testing.T) {
+	var c Contention
+
+	if err := c.Lock(func() error { return nil }); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if c.lockWaits.Load() != 0 {
+		t.Errorf("an uncontended lock was counted as a wait")
+	}
+
+	failed := errors.New("failed")
+	if err := c.Lock(func() error {
+		time.Sleep(lockWaitThreshold)
+		return failed
+	}); !errors.Is(err, failed) {
+		t.Errorf("Lock returned %v, want the statement's error", err)
+	}
+	if c.lockWaits.Load() != 1 || time.Duration(c.lockWait.Load()) < lockWaitThreshold {
+		t.Errorf("a blocked lock was counted as %d waits of %s", c.lockWaits.Load(), time.Duration(c.lockWait.Load()))
+	}
+}
+
+// Deadlocked transactions run again, and only the completed one counts as a reservation.
+func TestContentionReserveRetriesDeadlocks(t *testing.T) {
+	var c Contention
+
+	runs := 0
+	err := c.Reserve(func() (bool, error) {
+		runs++
+		if runs < 3 {
+			return false, &pq.Error{Code: deadlockDetected}
+		}
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+
+	if runs != 3 || c.deadlocks.Load() != 2 || c.reservations.Load() != 1 || c.rejections.Load() != 1 {
+		t.Errorf("%d runs counted %d deadlocks, %d reservations and %d rejections",
+			runs, c.deadlocks.Load(), c.reservations.Load(), c.rejections.Load())
+	}
+
+	other := &pq.Error{Code: "23505"}
+	if err := c.Reserve(func() (bool, error) { return false, other }); !errors.Is(err, other) {
+		t.Errorf("Reserve returned %v, want the transaction's error", err)
+	}
+}
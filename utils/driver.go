@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
+)
+
+// drivers maps the -driver values to the names the drivers register with database/sql.
+var drivers = map[string]string{
+	"pq":  "postgres",
+	"pgx": "pgx",
+}
+
+// OpenDB opens the DSN with the database/sql driver selected by -driver.
+// GORM and Ent are handed this handle, so every implementation runs on the same driver.
+func (c Config) OpenDB(dsn string) (*sql.DB, error) {
+	driver, ok := drivers[c.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unknown driver \"%s\"", c.Driver)
+	}
+
+	return sql.Open(driver, dsn)
+}
+
+// SQLState returns the SQLSTATE code of a PostgreSQL error reported by either driver, or "" for other errors.
+func SQLState(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	return ""
+}
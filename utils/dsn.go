@@ -0,0 +1,27 @@
+package utils
+
+import "strings"
+
+// DSNValue returns the value of a key in a keyword/value DSN.
+func DSNValue(dsn, key string) string {
+	for _, field := range strings.Fields(dsn) {
+		if k, v, ok := strings.Cut(field, "="); ok && k == key {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// WithDSNValue returns the keyword/value DSN with key set to value.
+func WithDSNValue(dsn, key, value string) string {
+	fields := strings.Fields(dsn)
+	for i, field := range fields {
+		if k, _, ok := strings.Cut(field, "="); ok && k == key {
+			fields[i] = key + "=" + value
+			return strings.Join(fields, " ")
+		}
+	}
+
+	return strings.Join(append(fields, key+"="+value), " ")
+}
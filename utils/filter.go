@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/yahn1ukov/go-orm-sql-efficiency/models"
+)
+
+// The created_at windows of the filters fall into the two years of history the dataset generates.
+var filterHistoryEnd = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+const filterHistoryDays = 730
+
+// ProductFilter is a combination of the optional filters of a product search.
+// Nil bounds, a false InStock and an empty Name are not applied.
+type ProductFilter struct {
+	MinPrice    *models.Money
+	MaxPrice    *models.Money
+	InStock     bool
+	Name        string // matched case-insensitively anywhere in the name
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+// FilterGenerator composes product filters from a seeded source, applying every filter with probability 1/2.
+type FilterGenerator struct {
+	rand *rand.Rand
+}
+
+func NewFilterGenerator(seed uint64) *FilterGenerator {
+	return &FilterGenerator{rand: newRand(seed)}
+}
+
+func (c Config) FilterGenerator(stream uint64) *FilterGenerator {
+	return NewFilterGenerator(c.Seed + stream)
+}
+
+func (g *FilterGenerator) Next() ProductFilter {
+	var f ProductFilter
+
+	if g.rand.IntN(2) == 0 {
+		price := models.Money(500 + g.rand.IntN(9500))
+		f.MinPrice = &price
+	}
+	if g.rand.IntN(2) == 0 {
+		price := models.Money(2000 + g.rand.IntN(48000))
+		if f.MinPrice != nil {
+			price += *f.MinPrice
+		}
+		f.MaxPrice = &price
+	}
+	f.InStock = g.rand.IntN(2) == 0
+	if g.rand.IntN(2) == 0 {
+		f.Name = "product " + strconv.Itoa(1+g.rand.IntN(9))
+	}
+	if g.rand.IntN(2) == 0 {
+		from := filterHistoryEnd.AddDate(0, 0, -filterHistoryDays+g.rand.IntN(filterHistoryDays))
+		to := from.AddDate(0, 0, 30+g.rand.IntN(335))
+		f.CreatedFrom, f.CreatedTo = &from, &to
+	}
+
+	return f
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+const (
+	zipfS = 1.1
+	zipfV = 1
+
+	hotspotKeys   = 0.2
+	hotspotAccess = 0.8
+)
+
+type Generator interface {
+	Next() int
+	// Len returns the number of values the generator draws from.
+	Len() int
+}
+
+// NextDistinct draws values from g until it has n different ones. It fails instead of drawing forever when
+// g has fewer than n values, so callers whose batch may exceed a small dataset clamp n to g.Len().
+func NextDistinct(g Generator, n int) ([]int, error) {
+	if n > g.Len() {
+		return nil, fmt.Errorf("cannot draw %d distinct values from %d", n, g.Len())
+	}
+
+	values := make([]int, 0, n)
+	seen := make(map[int]struct{}, n)
+	for len(values) < n {
+		v := g.Next()
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			values = append(values, v)
+		}
+	}
+
+	return values, nil
+}
+
+func NewGenerator(distribution string, values []int, seed uint64) (Generator, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no values to draw from for distribution \"%s\"", distribution)
+	}
+
+	switch distribution {
+	case "sequential":
+		return NewSequential(values), nil
+	case "uniform":
+		return NewUniform(values, seed), nil
+	case "zipf":
+		return NewZipf(values, seed), nil
+	case "hotspot":
+		return NewHotspot(values, seed), nil
+	default:
+		return nil, fmt.Errorf("unknown distribution \"%s\"", distribution)
+	}
+}
+
+// Sequential walks the values in order and wraps around at the end.
+type Sequential struct {
+	values []int
+	next   int
+}
+
+func NewSequential(values []int) *Sequential {
+	return &Sequential{values: values}
+}
+
+func (g *Sequential) Len() int {
+	return len(g.values)
+}
+
+func (g *Sequential) Next() int {
+	value := g.values[g.next]
+	g.next = (g.next + 1) % len(g.values)
+
+	return value
+}
+
+// Uniform draws every value with the same probability.
+type Uniform struct {
+	values []int
+	rand   *rand.Rand
+}
+
+func NewUniform(values []int, seed uint64) *Uniform {
+	return &Uniform{
+		values: values,
+		rand:   newRand(seed),
+	}
+}
+
+func (g *Uniform) Len() int {
+	return len(g.values)
+}
+
+func (g *Uniform) Next() int {
+	return g.values[g.rand.IntN(len(g.values))]
+}
+
+// Zipf draws values with a Zipf distribution over their position,
+// so the first values are requested far more often than the last ones.
+type Zipf struct {
+	values []int
+	zipf   *rand.Zipf
+}
+
+func NewZipf(values []int, seed uint64) *Zipf {
+	return &Zipf{
+		values: values,
+		zipf:   rand.NewZipf(newRand(seed), zipfS, zipfV, uint64(len(values)-1)),
+	}
+}
+
+func (g *Zipf) Len() int {
+	return len(g.values)
+}
+
+func (g *Zipf) Next() int {
+	return g.values[g.zipf.Uint64()]
+}
+
+// Hotspot sends most of the accesses to a small set of hot values
+// and spreads the rest uniformly over the remaining cold ones.
+type Hotspot struct {
+	values []int
+	hot    int
+	rand   *rand.Rand
+}
+
+func NewHotspot(values []int, seed uint64) *Hotspot {
+	return &Hotspot{
+		values: values,
+		hot:    max(int(float64(len(values))*hotspotKeys), 1),
+		rand:   newRand(seed),
+	}
+}
+
+func (g *Hotspot) Len() int {
+	return len(g.values)
+}
+
+func (g *Hotspot) Next() int {
+	if g.hot == len(g.values) || g.rand.Float64() < hotspotAccess {
+		return g.values[g.rand.IntN(g.hot)]
+	}
+
+	return g.values[g.hot+g.rand.IntN(len(g.values)-g.hot)]
+}
+
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
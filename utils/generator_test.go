@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func ids(n int) []int {
+	v := make([]int, n)
+	for i := range v {
+		v[i] = 1000 + i
+	}
+
+	return v
+}
+
+func draw(g Generator, n int) []int {
+	drawn := make([]int, n)
+	for i := range drawn {
+		drawn[i] = g.Next()
+	}
+
+	return drawn
+}
+
+func TestNewGeneratorErrors(t *testing.T) {
+	if _, err := NewGenerator("uniform", nil, 1); err == nil {
+		t.Error("NewGenerator accepted an empty value set")
+	}
+	if _, err := NewGenerator("gaussian", ids(3), 1); err == nil {
+		t.Error("NewGenerator accepted an unknown distribution")
+	}
+}
+
+func TestSequentialWraps(t *testing.T) {
+	got := draw(NewSequential([]int{30, 10, 20}), 7)
+	if want := []int{30, 10, 20, 30, 10, 20, 30}; !slices.Equal(got, want) {
+		t.Errorf("sequential drew %v, want %v", got, want)
+	}
+}
+
+// A single value is the smallest set every distribution has to handle:
+// Zipf gets imax 0 and Hotspot has no cold values left.
+func TestSingleValue(t *testing.T) {
+	for _, distribution := range []string{"sequential", "uniform", "zipf", "hotspot"} {
+		g, err := NewGenerator(distribution, []int{42}, 1)
+		if err != nil {
+			t.Fatalf("NewGenerator(%q) failed: %v", distribution, err)
+		}
+
+		for _, v := range draw(g, 100) {
+			if v != 42 {
+				t.Fatalf("%s drew %d from a single value set", distribution, v)
+			}
+		}
+	}
+}
+
+func TestSeedReproducible(t *testing.T) {
+	for _, distribution := range []string{"uniform", "zipf", "hotspot"} {
+		a, _ := NewGenerator(distribution, ids(50), 7)
+		b, _ := NewGenerator(distribution, ids(50), 7)
+		c, _ := NewGenerator(distribution, ids(50), 8)
+
+		first := draw(a, 200)
+		if !slices.Equal(first, draw(b, 200)) {
+			t.Errorf("%s drew different values for the same seed", distribution)
+		}
+		if slices.Equal(first, draw(c, 200)) {
+			t.Errorf("%s drew the same values for different seeds", distribution)
+		}
+	}
+}
+
+func TestZipfFavoursTheFirstValues(t *testing.T) {
+	v := ids(100)
+	counts := make(map[int]int)
+	for _, id := range draw(NewZipf(v, 1), 100000) {
+		counts[id]++
+	}
+
+	if counts[v[0]] <= counts[v[1]] || counts[v[1]] <= counts[v[10]] || counts[v[10]] <= counts[v[99]] {
+		t.Errorf("zipf counts do not fall with the position: %d, %d, %d, %d",
+			counts[v[0]], counts[v[1]], counts[v[10]], counts[v[99]])
+	}
+}
+
+func TestHotspotShare(t *testing.T) {
+	tests := []struct {
+		values int
+		hot    int
+	}{
+		{values: 100, hot: 20},
+		// Below five values the hot set is clamped to one value.
+		{values: 4, hot: 1},
+	}
+
+	for _, tt := range tests {
+		v := ids(tt.values)
+
+		const n = 100000
+		hot := 0
+		for _, id := range draw(NewHotspot(v, 1), n) {
+			if slices.Contains(v[:tt.hot], id) {
+				hot++
+			}
+		}
+
+		if share := float64(hot) / n; share < hotspotAccess-0.02 || share > hotspotAccess+0.02 {
+			t.Errorf("hotspot over %d values sent %.3f of the draws to %d hot values, want %.2f",
+				tt.values, share, tt.hot, hotspotAccess)
+		}
+	}
+}
+
+func TestNextDistinct(t *testing.T) {
+	distinct := func(g Generator, n int) []int {
+		t.Helper()
+
+		values, err := NextDistinct(g, n)
+		if err != nil {
+			t.Fatalf("NextDistinct(%d) failed: %v", n, err)
+		}
+
+		return values
+	}
+
+	// Sequential draws keep their order, so the batch is the next n values.
+	if got := distinct(NewSequential(ids(10)), 4); !slices.Equal(got, ids(4)) {
+		t.Errorf("NextDistinct over sequential = %v, want %v", got, ids(4))
+	}
+
+	// Zipf repeats the first values most of the time, but a batch
+	// the size of the whole set still has to reach the tail.
+	got := distinct(NewZipf(ids(20), 1), 20)
+	if slices.Sort(got); !slices.Equal(got, ids(20)) {
+		t.Errorf("NextDistinct over the whole zipf set = %v, want every value", got)
+	}
+
+	if got := distinct(NewUniform(ids(5), 1), 0); len(got) != 0 {
+		t.Errorf("NextDistinct(0) = %v, want no values", got)
+	}
+}
+
+// A batch larger than the dataset used to loop forever; it now fails,
+// and callers clamp the batch to Len instead.
+func TestNextDistinctSmallDataset(t *testing.T) {
+	for _, g := range []Generator{
+		NewSequential(ids(1)),
+		NewUniform(ids(3), 1),
+		NewZipf(ids(3), 1),
+		NewHotspot(ids(3), 1),
+	} {
+		if g.Len() != 1 && g.Len() != 3 {
+			t.Errorf("%T has Len %d", g, g.Len())
+		}
+		if values, err := NextDistinct(g, g.Len()+1); err == nil {
+			t.Errorf("NextDistinct(%d) over %T with %d values = %v, want an error", g.Len()+1, g, g.Len(), values)
+		}
+
+		values, err := NextDistinct(g, min(10, g.Len()))
+		if err != nil || len(values) != g.Len() {
+			t.Errorf("NextDistinct clamped to %d over %T = %v, %v", g.Len(), g, values, err)
+		}
+	}
+}
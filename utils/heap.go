@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+const (
+	// heapSampleInterval is how often the heap is sampled while an operation reporting rows runs.
+	heapSampleInterval = time.Millisecond
+	heapMetric         = "/memory/classes/heap/objects:bytes"
+)
+
+// heapSampler records the peak heap occupied by objects, reachable or not yet swept, until it is stopped.
+type heapSampler struct {
+	stop chan struct{}
+	peak chan uint64
+}
+
+func heapObjects() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+
+	return sample[0].Value.Uint64()
+}
+
+func sampleHeap() *heapSampler {
+	s := &heapSampler{
+		stop: make(chan struct{}),
+		peak: make(chan uint64),
+	}
+
+	go func() {
+		ticker := time.NewTicker(heapSampleInterval)
+		defer ticker.Stop()
+
+		peak := heapObjects()
+		for {
+			select {
+			case <-ticker.C:
+				peak = max(peak, heapObjects())
+			case <-s.stop:
+				s.peak <- max(peak, heapObjects())
+				return
+			}
+		}
+	}()
+
+	return s
+}
+
+// Stop stops sampling and returns the peak in bytes.
+func (s *heapSampler) Stop() uint64 {
+	close(s.stop)
+
+	return <-s.peak
+}
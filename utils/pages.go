@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// PageSize is the number of products per page of the pagination operations.
+const PageSize = 20
+
+// pageBuckets is the number of page ranges the latency of a paginated walk is reported in.
+const pageBuckets = 10
+
+// PageTimes accumulates the latency of every page of repeated paginated walks by page number.
+// The pagination operations walk the whole products table page by page, until a page has fewer than
+// PageSize rows, and check the number of rows read and the sum of their ids against the table.
+// Operations embedding it report the average page latency as the page depth grows.
+type PageTimes struct {
+	totals []time.Duration
+	counts []int
+}
+
+// Add records the latency of the page with the 0-based number page.
+func (p *PageTimes) Add(page int, d time.Duration) {
+	for len(p.totals) <= page {
+		p.totals = append(p.totals, 0)
+		p.counts = append(p.counts, 0)
+	}
+
+	p.totals[page] += d
+	p.counts[page]++
+}
+
+// Breakdown returns the average page latency in milliseconds of up to pageBuckets consecutive ranges of pages.
+func (p *PageTimes) Breakdown() []Label {
+	size := max((len(p.totals)+pageBuckets-1)/pageBuckets, 1)
+
+	var labels []Label
+	for start := 0; start < len(p.totals); start += size {
+		end := min(start+size, len(p.totals))
+
+		var total time.Duration
+		var count int
+		for page := start; page < end; page++ {
+			total += p.totals[page]
+			count += p.counts[page]
+		}
+
+		name := fmt.Sprintf("pages %d-%d", start+1, end)
+		if end == start+1 {
+			name = fmt.Sprintf("page %d", end)
+		}
+
+		labels = append(labels, Label{
+			Name:  name,
+			Value: fmt.Sprintf("%.4f ms", float64(total.Nanoseconds())/float64(max(count, 1))/1e6),
+		})
+	}
+
+	return labels
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// PhaseTimes accumulates the latency of the named phases of every execution, such as building a query
+// and running it. Operations embedding it report the average latency of every phase.
+type PhaseTimes struct {
+	phases []string
+	totals []time.Duration
+	counts []int
+}
+
+func (p *PhaseTimes) Add(phase string, d time.Duration) {
+	i := 0
+	for i < len(p.phases) && p.phases[i] != phase {
+		i++
+	}
+	if i == len(p.phases) {
+		p.phases = append(p.phases, phase)
+		p.totals = append(p.totals, 0)
+		p.counts = append(p.counts, 0)
+	}
+
+	p.totals[i] += d
+	p.counts[i]++
+}
+
+func (p *PhaseTimes) Breakdown() []Label {
+	labels := make([]Label, len(p.phases))
+	for i, phase := range p.phases {
+		labels[i] = Label{
+			Name:  phase,
+			Value: fmt.Sprintf("%.4f ms", float64(p.totals[i].Nanoseconds())/float64(p.counts[i])/1e6),
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WALVolume accumulates the write-ahead log written by every execution, read from the WAL insert position
+// before and after it. Operations embedding it call Start when an iteration is prepared and Stop when it is
+// cleaned up, and report the average volume per execution.
+type WALVolume struct {
+	start string
+	bytes int64
+	count int
+}
+
+func (w *WALVolume) Start(ctx context.Context, db *sql.DB) error {
+	return db.
+		QueryRowContext(ctx, "SELECT pg_current_wal_insert_lsn()::text").
+		Scan(&w.start)
+}
+
+func (w *WALVolume) Stop(ctx context.Context, db *sql.DB) error {
+	var bytes int64
+	if err := db.
+		QueryRowContext(ctx, "SELECT pg_wal_lsn_diff(pg_current_wal_insert_lsn(), $1::pg_lsn)::bigint", w.start).
+		Scan(&bytes); err != nil {
+		return fmt.Errorf("failed to read WAL position: %w", err)
+	}
+
+	w.bytes += bytes
+	w.count++
+
+	return nil
+}
+
+func (w *WALVolume) Breakdown() []Label {
+	if w.count == 0 {
+		return nil
+	}
+
+	return []Label{{
+		Name:  "WAL per execution",
+		Value: fmt.Sprintf("%.1f KB", float64(w.bytes)/float64(w.count)/1024),
+	}}
+}